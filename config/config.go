@@ -0,0 +1,335 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// OversizedRequestPolicy values.
+const (
+	OversizedReject = "reject"
+	OversizedSplit  = "split"
+)
+
+// MissingAlertnamePolicy values.
+const (
+	MissingAlertnameReject = "reject"
+	MissingAlertnameDrop   = "drop"
+)
+
+type Config struct {
+	Server        ServerConfig        `yaml:"server"`
+	Alerts        AlertsConfig        `yaml:"alerts"`
+	Heartbeat     HeartbeatConfig     `yaml:"heartbeat"`
+	Logging       LoggingConfig       `yaml:"logging"`
+	EventBridge   EventBridgeConfig   `yaml:"eventbridge"`
+	AMQP          AMQPConfig          `yaml:"amqp"`
+	Stdout        StdoutConfig        `yaml:"stdout"`
+	AdaptiveCard  AdaptiveCardConfig  `yaml:"adaptivecard"`
+	PowerAutomate PowerAutomateConfig `yaml:"powerautomate"`
+	Enrichment    EnrichmentConfig    `yaml:"enrichment"`
+	History       HistoryConfig       `yaml:"history"`
+	Silences      SilencesConfig      `yaml:"silences"`
+}
+
+type ServerConfig struct {
+	Address        string     `yaml:"address"`
+	MetricsAddress string     `yaml:"metrics_address"`
+	MetricsPath    string     `yaml:"metrics_path"`
+	CORS           CORSConfig `yaml:"cors"`
+	MetricsAuth    AuthConfig `yaml:"metrics_auth"`
+	PprofEnabled   bool       `yaml:"pprof_enabled"`
+
+	// RequestTimeout is the deadline for processing a request to /alerts,
+	// after which the client gets 504. Zero disables it.
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+
+	// ShutdownTimeout bounds how long in-flight requests may take to finish
+	// on shutdown before their connections are closed.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+
+	// ReadTimeout, ReadHeaderTimeout, WriteTimeout and IdleTimeout are set
+	// on both HTTP servers. WriteTimeout defaults to zero (none) because it
+	// would cut off long-lived /alerts/stream connections; RequestTimeout
+	// bounds /alerts instead.
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+
+	// AdminAuth protects the /admin, /processors, /silences, /alerts/stream,
+	// /alerts/test and /alerts/search endpoints, which are only served when
+	// it is configured.
+	AdminAuth AuthConfig `yaml:"admin_auth"`
+
+	// TrustedProxies lists the CIDRs whose X-Forwarded-For header is
+	// trusted when resolving the client IP for request logs.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// AlertsConfig controls how received alerts are prepared before they are
+// dispatched to processors.
+type AlertsConfig struct {
+	// DefaultLabels and DefaultAnnotations are added to every alert that
+	// does not already set the key.
+	DefaultLabels      map[string]string `yaml:"default_labels"`
+	DefaultAnnotations map[string]string `yaml:"default_annotations"`
+
+	// Normalize trims whitespace and lowercases the values of
+	// NormalizeLabels (severity and status when unset) before dispatch.
+	Normalize       bool     `yaml:"normalize"`
+	NormalizeLabels []string `yaml:"normalize_labels"`
+
+	// DedupInRequest drops alerts that repeat the status and label set of
+	// an earlier alert in the same request.
+	DedupInRequest bool `yaml:"dedup_in_request"`
+
+	// MaxAlertsPerRequest limits the number of alerts in one request. With
+	// OversizedRequestPolicy "reject" (the default) larger requests fail with
+	// 413 and nothing is dispatched; with "split" they are dispatched in
+	// batches of at most MaxAlertsPerRequest. Zero means no limit.
+	MaxAlertsPerRequest    int    `yaml:"max_alerts_per_request"`
+	OversizedRequestPolicy string `yaml:"oversized_request_policy"`
+
+	// RequireAlertname handles alerts without an alertname label according
+	// to MissingAlertnamePolicy: "reject" (the default) fails the request
+	// with 400, "drop" skips the alert. The check runs after DefaultLabels
+	// are merged, so a default alertname satisfies it.
+	RequireAlertname       bool   `yaml:"require_alertname"`
+	MissingAlertnamePolicy string `yaml:"missing_alertname_policy"`
+
+	// StrictTimestamps rejects requests containing an alert whose StartsAt
+	// or EndsAt is not RFC3339. Otherwise such timestamps are cleared.
+	StrictTimestamps bool `yaml:"strict_timestamps"`
+
+	// CorrelationID sets the correlation_id annotation on alerts that lack
+	// it, rendered from CorrelationIDTemplate (a text/template executed
+	// against the alert) or the alert's label fingerprint by default. The
+	// fingerprint is also used when the template references a missing label.
+	CorrelationID         bool   `yaml:"correlation_id"`
+	CorrelationIDTemplate string `yaml:"correlation_id_template"`
+
+	// MaxAlertAge drops firing alerts whose StartsAt is older than this.
+	// Zero disables the check.
+	MaxAlertAge time.Duration `yaml:"max_alert_age"`
+
+	// RequireFiringBeforeResolved drops resolved alerts that were not seen
+	// firing since startup, except during ResolvedGracePeriod after it.
+	// Firing state not refreshed within ResolvedStateTTL is forgotten; it
+	// should exceed Alertmanager's repeat_interval. Zero keeps it forever.
+	RequireFiringBeforeResolved bool          `yaml:"require_firing_before_resolved"`
+	ResolvedGracePeriod         time.Duration `yaml:"resolved_grace_period"`
+	ResolvedStateTTL            time.Duration `yaml:"resolved_state_ttl"`
+}
+
+// CORSConfig controls the CORS headers returned by the /alerts and
+// /alerts/stream routes.
+// CORS is disabled when AllowedOrigins is empty.
+type CORSConfig struct {
+	AllowedOrigins []string `yaml:"allowed_origins"`
+	AllowedMethods []string `yaml:"allowed_methods"`
+	AllowedHeaders []string `yaml:"allowed_headers"`
+}
+
+// AuthConfig protects an endpoint with Basic Auth and/or a bearer token.
+// Authentication is disabled when no credentials are set.
+type AuthConfig struct {
+	Username    string `yaml:"username"`
+	Password    string `yaml:"password"`
+	BearerToken string `yaml:"bearer_token"`
+}
+
+func (c AuthConfig) Enabled() bool {
+	return c.Username != "" || c.BearerToken != ""
+}
+
+type LoggingConfig struct {
+	// RedactKeys lists label and annotation keys whose values are replaced
+	// with *** wherever alerts are logged.
+	RedactKeys []string `yaml:"redact_keys"`
+}
+
+// SinkOptions are shared by every alert sink. MinSeverity limits the sink
+// to alerts at or above that severity. RedactLabels and RedactAnnotations
+// name keys stripped from the copy of each alert the sink receives.
+type SinkOptions struct {
+	MinSeverity       string   `yaml:"min_severity"`
+	RedactLabels      []string `yaml:"redact_labels"`
+	RedactAnnotations []string `yaml:"redact_annotations"`
+}
+
+// StdoutConfig enables writing every alert to stdout in Format ("json" or
+// "line").
+type StdoutConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	Format      string `yaml:"format"`
+	SinkOptions `yaml:",inline"`
+}
+
+// AdaptiveCardConfig enables posting every alert as an Adaptive Card to
+// WebhookURL. CardTemplate optionally overrides the default card; values
+// interpolated into it should use the json helper, e.g.
+// {{json .Labels.alertname}}, to stay valid JSON.
+type AdaptiveCardConfig struct {
+	WebhookURL   string `yaml:"webhook_url"`
+	CardTemplate string `yaml:"card_template"`
+	SinkOptions  `yaml:",inline"`
+}
+
+// PowerAutomateConfig enables triggering a Power Automate flow at FlowURL
+// for every alert.
+type PowerAutomateConfig struct {
+	FlowURL     string `yaml:"flow_url"`
+	SinkOptions `yaml:",inline"`
+}
+
+// EnrichmentConfig points at a YAML file mapping label values to extra
+// labels and annotations. The file is re-read on SIGHUP.
+type EnrichmentConfig struct {
+	MappingFile string `yaml:"mapping_file"`
+}
+
+// HistoryConfig enables retaining dispatched alerts in memory for
+// Retention, searchable at /alerts/search when admin_auth is configured.
+// MaxEntries caps how many are kept regardless of age; zero means no cap.
+type HistoryConfig struct {
+	Retention  time.Duration `yaml:"retention"`
+	MaxEntries int           `yaml:"max_entries"`
+}
+
+// SilencesConfig sets Path, a JSON file the silences created through
+// /silences are saved to and reloaded from on start. Without it silences
+// are kept in memory only and are lost on restart.
+type SilencesConfig struct {
+	Path string `yaml:"path"`
+}
+
+// HeartbeatConfig makes the handler ping URL every Interval so external
+// monitoring can tell it is alive. The heartbeat is off when URL is empty.
+type HeartbeatConfig struct {
+	URL      string        `yaml:"url"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+// EventBridgeConfig enables putting every alert onto the EventBridge bus
+// EventBusName as an event with Source and DetailType. Region defaults to
+// the one in the AWS environment. The handler must be built with
+// -tags eventbridge.
+type EventBridgeConfig struct {
+	EventBusName string `yaml:"event_bus_name"`
+	Source       string `yaml:"source"`
+	DetailType   string `yaml:"detail_type"`
+	Region       string `yaml:"region"`
+	SinkOptions  `yaml:",inline"`
+}
+
+// AMQPConfig enables publishing every alert to Exchange on the broker at
+// URL with RoutingKey. When ExchangeType is set the exchange is declared
+// as durable on connect; otherwise it must already exist.
+type AMQPConfig struct {
+	URL          string `yaml:"url"`
+	Exchange     string `yaml:"exchange"`
+	RoutingKey   string `yaml:"routing_key"`
+	ExchangeType string `yaml:"exchange_type"`
+	SinkOptions  `yaml:",inline"`
+}
+
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Address:           ":8080",
+			MetricsAddress:    ":2112",
+			MetricsPath:       "/metrics",
+			ShutdownTimeout:   15 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			ReadHeaderTimeout: 10 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+		},
+		Alerts: AlertsConfig{
+			ResolvedStateTTL: 24 * time.Hour,
+		},
+		Heartbeat: HeartbeatConfig{
+			Interval: time.Minute,
+		},
+		History: HistoryConfig{
+			MaxEntries: 10000,
+		},
+	}
+}
+
+// LoadConfig reads a config file on top of the defaults. Files ending in
+// .json are parsed as JSON, anything else as YAML. URLs are fetched with
+// LoadConfigFromURL. An empty path returns the defaults unchanged.
+func LoadConfig(path string) (*Config, error) {
+	if path == "" {
+		return Default(), nil
+	}
+	if isURL(path) {
+		return LoadConfigFromURL(path)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return LoadConfigJSON(path)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parse(data)
+}
+
+// LoadConfigJSON reads a JSON config file on top of the defaults. It uses
+// the same keys as the YAML format, including duration strings like "30s".
+func LoadConfigJSON(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("%s is not valid JSON", path)
+	}
+	// JSON is a subset of YAML, so the YAML decoder handles it with the
+	// same field names and duration parsing.
+	return parse(data)
+}
+
+func parse(data []byte) (*Config, error) {
+	cfg := Default()
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) validate() error {
+	if !strings.HasPrefix(c.Server.MetricsPath, "/") {
+		return fmt.Errorf("invalid server.metrics_path %q: must start with \"/\"", c.Server.MetricsPath)
+	}
+	switch c.Alerts.OversizedRequestPolicy {
+	case "", OversizedReject, OversizedSplit:
+	default:
+		return fmt.Errorf("invalid alerts.oversized_request_policy %q: must be %q or %q",
+			c.Alerts.OversizedRequestPolicy, OversizedReject, OversizedSplit)
+	}
+	switch c.Alerts.MissingAlertnamePolicy {
+	case "", MissingAlertnameReject, MissingAlertnameDrop:
+	default:
+		return fmt.Errorf("invalid alerts.missing_alertname_policy %q: must be %q or %q",
+			c.Alerts.MissingAlertnamePolicy, MissingAlertnameReject, MissingAlertnameDrop)
+	}
+	if c.Alerts.CorrelationIDTemplate != "" {
+		if _, err := template.New("correlation_id").Option("missingkey=error").Parse(c.Alerts.CorrelationIDTemplate); err != nil {
+			return fmt.Errorf("invalid alerts.correlation_id_template: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/igormishsky/prometheus-alerts-handler/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := []byte(`
+server:
+  address: ":9090"
+  cors:
+    allowed_origins: ["https://dashboard.example.com"]
+    allowed_methods: ["POST"]
+alerts:
+  max_alert_age: 1h30m
+`)
+	assert.NoError(t, ioutil.WriteFile(path, data, 0600))
+
+	cfg, err := config.LoadConfig(path)
+	assert.NoError(t, err)
+
+	assert.Equal(t, ":9090", cfg.Server.Address)
+	assert.Equal(t, ":2112", cfg.Server.MetricsAddress)
+	assert.Equal(t, []string{"https://dashboard.example.com"}, cfg.Server.CORS.AllowedOrigins)
+	assert.Equal(t, []string{"POST"}, cfg.Server.CORS.AllowedMethods)
+	assert.Empty(t, cfg.Server.CORS.AllowedHeaders)
+	assert.Equal(t, 90*time.Minute, cfg.Alerts.MaxAlertAge)
+}
+
+func TestLoadConfig_EmptyPath(t *testing.T) {
+	cfg, err := config.LoadConfig("")
+	assert.NoError(t, err)
+	assert.Equal(t, config.Default(), cfg)
+}
+
+func TestLoadConfig_JSONMatchesYAML(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlPath := filepath.Join(dir, "config.yaml")
+	assert.NoError(t, ioutil.WriteFile(yamlPath, []byte(`
+server:
+  address: ":9090"
+  shutdown_timeout: 30s
+  cors:
+    allowed_origins: ["https://dashboard.example.com"]
+alerts:
+  default_labels:
+    team: platform
+  max_alert_age: 2h
+logging:
+  redact_keys: ["password"]
+`), 0600))
+
+	jsonPath := filepath.Join(dir, "config.json")
+	assert.NoError(t, ioutil.WriteFile(jsonPath, []byte(`{
+  "server": {
+    "address": ":9090",
+    "shutdown_timeout": "30s",
+    "cors": {"allowed_origins": ["https://dashboard.example.com"]}
+  },
+  "alerts": {
+    "default_labels": {"team": "platform"},
+    "max_alert_age": "2h"
+  },
+  "logging": {"redact_keys": ["password"]}
+}`), 0600))
+
+	fromYAML, err := config.LoadConfig(yamlPath)
+	assert.NoError(t, err)
+	fromJSON, err := config.LoadConfig(jsonPath)
+	assert.NoError(t, err)
+
+	assert.Equal(t, fromYAML, fromJSON)
+	assert.Equal(t, 30*time.Second, fromJSON.Server.ShutdownTimeout)
+	assert.Equal(t, ":2112", fromJSON.Server.MetricsAddress)
+}
+
+func TestLoadConfigJSON_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("server:\n  address: \":9090\"\n"), 0600))
+
+	_, err := config.LoadConfigJSON(path)
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"correlation id template", "alerts:\n  correlation_id_template: \"{{.Labels\"\n"},
+		{"missing alertname policy", "alerts:\n  missing_alertname_policy: dorp\n"},
+		{"oversized request policy", "alerts:\n  oversized_request_policy: splt\n"},
+		{"empty metrics path", "server:\n  metrics_path: \"\"\n"},
+		{"relative metrics path", "server:\n  metrics_path: metrics\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			assert.NoError(t, ioutil.WriteFile(path, []byte(tt.data), 0600))
+
+			_, err := config.LoadConfig(path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadConfig_SinkOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := []byte(`
+adaptivecard:
+  webhook_url: https://chat.example.com/hook
+  min_severity: warning
+  redact_labels: [api_key]
+  redact_annotations: [internal_url]
+`)
+	assert.NoError(t, ioutil.WriteFile(path, data, 0600))
+
+	cfg, err := config.LoadConfig(path)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "https://chat.example.com/hook", cfg.AdaptiveCard.WebhookURL)
+	assert.Equal(t, config.SinkOptions{
+		MinSeverity:       "warning",
+		RedactLabels:      []string{"api_key"},
+		RedactAnnotations: []string{"internal_url"},
+	}, cfg.AdaptiveCard.SinkOptions)
+}
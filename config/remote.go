@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxRemoteConfigBytes caps how much of a remote config is read.
+const maxRemoteConfigBytes = 1 << 20
+
+// ObjectFetcher reads an object from a bucket.
+type ObjectFetcher interface {
+	Fetch(bucket, key string) ([]byte, error)
+}
+
+// S3Fetcher serves s3://bucket/key sources. Building with -tags s3 sets it
+// to a fetcher using the AWS SDK and its default credential chain.
+// Otherwise it is nil and s3:// URLs return an error, unless the embedding
+// program assigns its own.
+var S3Fetcher ObjectFetcher
+
+var remoteClient = &http.Client{Timeout: 30 * time.Second}
+
+// LoadConfigFromURL fetches a config from an http(s):// or s3:// URL and
+// parses it on top of the defaults. JSON and YAML bodies are both accepted.
+func LoadConfigFromURL(rawURL string) (*Config, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		data, err = fetchHTTP(rawURL)
+	case "s3":
+		if S3Fetcher == nil {
+			return nil, fmt.Errorf("no S3 fetcher configured for %s", rawURL)
+		}
+		data, err = S3Fetcher.Fetch(u.Host, strings.TrimPrefix(u.Path, "/"))
+	default:
+		return nil, fmt.Errorf("unsupported config URL scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parse(data)
+}
+
+func fetchHTTP(rawURL string) ([]byte, error) {
+	resp, err := remoteClient.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: status %d", rawURL, resp.StatusCode)
+	}
+	return readLimited(resp.Body, rawURL)
+}
+
+// readLimited reads r up to maxRemoteConfigBytes, failing rather than
+// truncating when there is more.
+func readLimited(r io.Reader, source string) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxRemoteConfigBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxRemoteConfigBytes {
+		return nil, fmt.Errorf("config at %s exceeds %d bytes", source, maxRemoteConfigBytes)
+	}
+	return data, nil
+}
+
+func isURL(path string) bool {
+	return strings.Contains(path, "://")
+}
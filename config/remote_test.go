@@ -0,0 +1,70 @@
+package config_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/igormishsky/prometheus-alerts-handler/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeS3 map[string]string
+
+func (f fakeS3) Fetch(bucket, key string) ([]byte, error) {
+	return []byte(f[bucket+"/"+key]), nil
+}
+
+func TestLoadConfigFromURL_HTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/handler.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("server:\n  address: \":9191\"\n"))
+	}))
+	defer server.Close()
+
+	cfg, err := config.LoadConfigFromURL(server.URL + "/handler.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, ":9191", cfg.Server.Address)
+	assert.Equal(t, ":2112", cfg.Server.MetricsAddress)
+
+	cfg, err = config.LoadConfig(server.URL + "/handler.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, ":9191", cfg.Server.Address)
+
+	_, err = config.LoadConfigFromURL(server.URL + "/missing.yaml")
+	assert.Error(t, err)
+}
+
+func TestLoadConfigFromURL_SizeLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("# " + strings.Repeat("x", 2<<20) + "\n"))
+	}))
+	defer server.Close()
+
+	_, err := config.LoadConfigFromURL(server.URL)
+	assert.Error(t, err)
+}
+
+func TestLoadConfigFromURL_S3(t *testing.T) {
+	previous := config.S3Fetcher
+	defer func() { config.S3Fetcher = previous }()
+
+	config.S3Fetcher = nil
+	_, err := config.LoadConfigFromURL("s3://configs/handler.yaml")
+	assert.Error(t, err)
+
+	config.S3Fetcher = fakeS3{"configs/handler.yaml": "server:\n  address: \":9292\"\n"}
+
+	cfg, err := config.LoadConfigFromURL("s3://configs/handler.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, ":9292", cfg.Server.Address)
+}
+
+func TestLoadConfigFromURL_UnsupportedScheme(t *testing.T) {
+	_, err := config.LoadConfigFromURL("ftp://example.com/handler.yaml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,59 @@
+//go:build s3
+// +build s3
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"os"
+	"strings"
+)
+
+func init() {
+	S3Fetcher = sdkS3Fetcher{}
+}
+
+// sdkS3Fetcher reads objects with the AWS SDK. Credentials and region come
+// from the SDK's default chain: environment, shared config and SSO, web
+// identity, and the EC2/ECS instance role. AWS_ENDPOINT_URL_S3 points it at
+// an S3-compatible endpoint.
+type sdkS3Fetcher struct{}
+
+func (sdkS3Fetcher) Fetch(bucket, key string) ([]byte, error) {
+	source := "s3://" + bucket + "/" + key
+	ctx, cancel := context.WithTimeout(context.Background(), remoteClient.Timeout)
+	defer cancel()
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS config for %s: %v", source, err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+
+	endpoint := os.Getenv("AWS_ENDPOINT_URL_S3")
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint != "" {
+			o.EndpointResolver = s3.EndpointResolverFromURL(endpoint)
+		}
+		// A dotted bucket name as a virtual-hosted subdomain does not match
+		// the *.s3 wildcard certificate, and custom endpoints generally
+		// expect the bucket in the path.
+		o.UsePathStyle = endpoint != "" || strings.Contains(bucket, ".")
+	})
+
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %v", source, err)
+	}
+	defer out.Body.Close()
+	return readLimited(out.Body, source)
+}
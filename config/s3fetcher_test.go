@@ -0,0 +1,76 @@
+//go:build s3
+// +build s3
+
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setS3Env points the SDK at endpoint with static credentials and restores
+// the previous environment when the test ends.
+func setS3Env(t *testing.T, endpoint string) {
+	for k, v := range map[string]string{
+		"AWS_ACCESS_KEY_ID":         "AKIDTEST",
+		"AWS_SECRET_ACCESS_KEY":     "secret",
+		"AWS_REGION":                "eu-west-1",
+		"AWS_ENDPOINT_URL_S3":       endpoint,
+		"AWS_EC2_METADATA_DISABLED": "true",
+	} {
+		old, ok := os.LookupEnv(k)
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(k, old)
+			} else {
+				os.Unsetenv(k)
+			}
+		})
+		os.Setenv(k, v)
+	}
+}
+
+func TestSDKS3Fetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/configs.example.com/prod/handler%3Dv2.yaml", r.URL.EscapedPath())
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDTEST/"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/s3/aws4_request")
+		w.Write([]byte("server:\n  address: \":9393\"\n"))
+	}))
+	defer server.Close()
+	setS3Env(t, server.URL)
+
+	cfg, err := LoadConfigFromURL("s3://configs.example.com/prod/handler=v2.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, ":9393", cfg.Server.Address)
+}
+
+func TestSDKS3Fetcher_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"missing object", http.StatusNotFound, "<Error><Code>NoSuchKey</Code></Error>"},
+		{"oversized object", http.StatusOK, strings.Repeat("a", maxRemoteConfigBytes+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+			setS3Env(t, server.URL)
+
+			_, err := LoadConfigFromURL("s3://configs/handler.yaml")
+			assert.Error(t, err)
+		})
+	}
+}
@@ -3,8 +3,14 @@ module github.com/igormishsky/prometheus-alerts-handler
 go 1.16
 
 require (
+	github.com/aws/aws-sdk-go-v2 v1.17.8
+	github.com/aws/aws-sdk-go-v2/config v1.18.21
+	github.com/aws/aws-sdk-go-v2/service/eventbridge v1.18.9
+	github.com/aws/aws-sdk-go-v2/service/s3 v1.31.3
 	github.com/gorilla/mux v1.8.0
 	github.com/prometheus/client_golang v1.14.0
+	github.com/rabbitmq/amqp091-go v1.5.0
 	github.com/sirupsen/logrus v1.8.1
 	github.com/stretchr/testify v1.7.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+// PauseHandler stops dispatching alerts until ResumeHandler is called.
+// Alerts are still received and counted while paused.
+func PauseHandler(pp *processors.PausableProcessor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pp.Pause()
+		logrus.Warn("Alert dispatch paused")
+		respondWithJSON(w, http.StatusOK, map[string]bool{"paused": true})
+	}
+}
+
+func ResumeHandler(pp *processors.PausableProcessor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pp.Resume()
+		logrus.Info("Alert dispatch resumed")
+		respondWithJSON(w, http.StatusOK, map[string]bool{"paused": false})
+	}
+}
+
+// HealthHandler reports that the handler is up and whether dispatch is
+// paused.
+func HealthHandler(pp *processors.PausableProcessor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"paused": pp.Paused(),
+		})
+	}
+}
+
+func respondWithJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,63 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/igormishsky/prometheus-alerts-handler/config"
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/metrics"
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func healthPaused(t *testing.T, pp *processors.PausableProcessor) bool {
+	req := httptest.NewRequest("GET", "/health", nil)
+	rr := httptest.NewRecorder()
+	handler.HealthHandler(pp).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var body struct {
+		Status string `json:"status"`
+		Paused bool   `json:"paused"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, "ok", body.Status)
+	return body.Paused
+}
+
+func postAlert(pp *processors.PausableProcessor) *httptest.ResponseRecorder {
+	body := `[{"status": "firing", "labels": {"alertname": "PauseTest"}}]`
+	req := httptest.NewRequest("POST", "/alerts", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	handler.NewAlertsHandler(config.AlertsConfig{}, pp).ServeHTTP(rr, req)
+	return rr
+}
+
+func TestPauseResume(t *testing.T) {
+	processor := &recordingProcessor{}
+	pp := &processors.PausableProcessor{Next: processor}
+	paused := metrics.AlertsSuppressed.WithLabelValues("paused")
+	before := testutil.ToFloat64(paused)
+
+	rr := httptest.NewRecorder()
+	handler.PauseHandler(pp).ServeHTTP(rr, httptest.NewRequest("POST", "/admin/pause", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, healthPaused(t, pp))
+
+	assert.Equal(t, http.StatusOK, postAlert(pp).Code)
+	assert.Equal(t, 0, processor.count())
+	assert.Equal(t, before+1, testutil.ToFloat64(paused))
+
+	rr = httptest.NewRecorder()
+	handler.ResumeHandler(pp).ServeHTTP(rr, httptest.NewRequest("POST", "/admin/resume", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.False(t, healthPaused(t, pp))
+
+	assert.Equal(t, http.StatusOK, postAlert(pp).Code)
+	assert.Equal(t, 1, processor.count())
+}
@@ -1,50 +1,283 @@
 package handler
 
 import (
+	"compress/gzip"
+	"context"
 	"encoding/json"
+	"fmt"
+	"github.com/igormishsky/prometheus-alerts-handler/config"
 	"github.com/igormishsky/prometheus-alerts-handler/metrics"
 	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/igormishsky/prometheus-alerts-handler/redact"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
 	"github.com/sirupsen/logrus"
-	"io/ioutil"
+	"io"
 	"net/http"
+	"strings"
+	"text/template"
+	"time"
 )
 
-type Alert struct {
-	Status      string            `json:"status"`
-	Labels      map[string]string `json:"labels"`
-	Annotations map[string]string `json:"annotations"`
-}
+type Alert = types.Alert
+
+var defaultNormalizeLabels = []string{"severity", "status"}
 
+// AlertsHandler dispatches received alerts to a BasicProcessor.
 func AlertsHandler(w http.ResponseWriter, r *http.Request) {
-	body, err := ioutil.ReadAll(r.Body)
+	NewAlertsHandler(config.AlertsConfig{}, &processors.BasicProcessor{})(w, r)
+}
+
+// NewAlertsHandler returns a handler that decodes the alert array from the
+// request body (gzip-compressed when Content-Encoding says so) one element
+// at a time and hands each alert to p as soon as it is decoded, so large
+// batches are never held in memory as a whole. When a per-request limit is
+// configured, alerts are held back until a full batch (or, with the reject
+// policy, the whole request) has been accepted.
+func NewAlertsHandler(cfg config.AlertsConfig, p processors.AlertProcessor) http.HandlerFunc {
+	correlationID := correlationIDTemplate(cfg)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		body := r.Body
+		if strings.EqualFold(r.Header.Get("Content-Encoding"), "gzip") {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				logrus.Error("Error decompressing request body:", err)
+				respondWithError(w, ErrCodeInvalidEncoding, "Error decompressing request body")
+				return
+			}
+			defer gz.Close()
+			body = gz
+		}
+
+		decoder := json.NewDecoder(body)
+
+		if err := expectDelim(decoder, '['); err != nil {
+			if err == io.EOF {
+				respondWithError(w, ErrCodeEmptyPayload, "Request body is empty")
+				return
+			}
+			logrus.Error("Error unmarshalling request body:", err)
+			respondWithError(w, ErrCodeInvalidJSON, "Error unmarshalling request body")
+			return
+		}
+
+		metrics.AlertsReceived.Inc()
+		seen := make(map[string]struct{})
+		flushAt := batchSize(cfg)
+		var pending []Alert
+		for index := 0; decoder.More(); index++ {
+			if cfg.MaxAlertsPerRequest > 0 && index >= cfg.MaxAlertsPerRequest && cfg.OversizedRequestPolicy != config.OversizedSplit {
+				logrus.Error("Request exceeds ", cfg.MaxAlertsPerRequest, " alerts")
+				respondWithError(w, ErrCodePayloadTooLarge, fmt.Sprintf("Request contains more than %d alerts", cfg.MaxAlertsPerRequest))
+				return
+			}
+
+			var alert Alert
+			if err := decoder.Decode(&alert); err != nil {
+				logrus.Error("Error unmarshalling request body:", err)
+				respondWithError(w, ErrCodeInvalidJSON, "Error unmarshalling request body")
+				return
+			}
+
+			logrus.Info("Received alert:", redact.Alert(alert))
+			if err := validateTimestamps(&alert, cfg.StrictTimestamps); err != nil {
+				logrus.Error("Invalid alert timestamp:", err)
+				respondWithError(w, ErrCodeInvalidTimestamp, "Invalid alert timestamp: "+err.Error())
+				return
+			}
+			prepareAlert(cfg, &alert)
+			if cfg.RequireAlertname && alert.Labels["alertname"] == "" {
+				if cfg.MissingAlertnamePolicy != config.MissingAlertnameDrop {
+					logrus.Error("Alert at index ", index, " has no alertname label")
+					respondWithError(w, ErrCodeMissingAlertname, fmt.Sprintf("Alert at index %d has no alertname label", index))
+					return
+				}
+				logrus.Warn("Dropping alert without alertname label:", redact.Alert(alert))
+				metrics.AlertsSuppressed.WithLabelValues("missing_alertname").Inc()
+				continue
+			}
+
+			if cfg.CorrelationID {
+				setCorrelationID(&alert, correlationID)
+			}
+
+			if isStale(cfg.MaxAlertAge, alert, time.Now()) {
+				logrus.Warn("Dropping stale alert started at ", alert.StartsAt)
+				metrics.AlertsSuppressed.WithLabelValues("stale").Inc()
+				continue
+			}
+
+			if cfg.DedupInRequest {
+				key := alert.Status + "/" + alert.Fingerprint()
+				if _, ok := seen[key]; ok {
+					logrus.Info("Dropping duplicate alert in request:", redact.Alert(alert))
+					metrics.AlertsSuppressed.WithLabelValues("dedup").Inc()
+					continue
+				}
+				seen[key] = struct{}{}
+			}
+
+			pending = append(pending, alert)
+			if len(pending) == flushAt {
+				if err := dispatch(r.Context(), p, pending); err != nil {
+					logrus.Error("Stopped dispatching request:", err)
+					return
+				}
+				pending = pending[:0]
+			}
+		}
+
+		if err := expectDelim(decoder, ']'); err != nil {
+			logrus.Error("Error unmarshalling request body:", err)
+			respondWithError(w, ErrCodeInvalidJSON, "Error unmarshalling request body")
+			return
+		}
+		if err := dispatch(r.Context(), p, pending); err != nil {
+			logrus.Error("Stopped dispatching request:", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Alerts received"))
+	}
+}
+
+// batchSize returns how many accepted alerts are held before they are
+// dispatched. Zero means alerts are only dispatched once the whole request
+// has been read, which is needed whenever a later alert can still get the
+// request rejected.
+func batchSize(cfg config.AlertsConfig) int {
+	switch {
+	case cfg.StrictTimestamps, cfg.RequireAlertname && cfg.MissingAlertnamePolicy != config.MissingAlertnameDrop:
+		return 0
+	case cfg.MaxAlertsPerRequest <= 0:
+		return 1
+	case cfg.OversizedRequestPolicy == config.OversizedSplit:
+		return cfg.MaxAlertsPerRequest
+	default:
+		return 0
+	}
+}
+
+// dispatch hands alerts to p in order. It stops as soon as ctx is done,
+// e.g. once RequestTimeout has answered the client, so an alert the client
+// will resend is not delivered twice.
+func dispatch(ctx context.Context, p processors.AlertProcessor, alerts []Alert) error {
+	for _, alert := range alerts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		p.Process(alert)
+	}
+	return nil
+}
+
+// prepareAlert normalizes alert and applies the configured defaults before
+// dispatch.
+func prepareAlert(cfg config.AlertsConfig, alert *Alert) {
+	if cfg.Normalize {
+		normalizeLabels(cfg.NormalizeLabels, alert.Labels)
+	}
+	alert.Labels = types.MergeMissing(alert.Labels, cfg.DefaultLabels)
+	alert.Annotations = types.MergeMissing(alert.Annotations, cfg.DefaultAnnotations)
+}
+
+// validateTimestamps checks that StartsAt and EndsAt are empty or RFC3339. In
+// strict mode an invalid timestamp is an error; otherwise it is cleared so
+// downstream logic treats it as unknown.
+func validateTimestamps(alert *Alert, strict bool) error {
+	for _, field := range []struct {
+		name  string
+		value *string
+	}{
+		{"startsAt", &alert.StartsAt},
+		{"endsAt", &alert.EndsAt},
+	} {
+		if *field.value == "" {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, *field.value); err != nil {
+			if strict {
+				return fmt.Errorf("%s %q is not RFC3339", field.name, *field.value)
+			}
+			logrus.Warn("Clearing invalid ", field.name, ": ", *field.value)
+			*field.value = ""
+		}
+	}
+	return nil
+}
+
+// isStale reports whether a firing alert started more than maxAge before now.
+// Resolved alerts and alerts without a parseable StartsAt are never stale.
+func isStale(maxAge time.Duration, alert Alert, now time.Time) bool {
+	if maxAge <= 0 || alert.Status == "resolved" || alert.StartsAt == "" {
+		return false
+	}
+
+	startsAt, err := time.Parse(time.RFC3339, alert.StartsAt)
 	if err != nil {
-		logrus.Error("Error reading request body:", err)
-		respondWithError(w, http.StatusBadRequest, "Error reading request body")
-		return
+		return false
 	}
 
-	var alerts []Alert
-	if err := json.Unmarshal(body, &alerts); err != nil {
-		logrus.Error("Error unmarshalling request body:", err)
-		respondWithError(w, http.StatusBadRequest, "Error unmarshalling request body")
+	return now.Sub(startsAt) > maxAge
+}
+
+// correlationIDTemplate parses the configured correlation ID template. It
+// returns nil, meaning the fingerprint is used, when none is configured or
+// it does not parse.
+func correlationIDTemplate(cfg config.AlertsConfig) *template.Template {
+	if cfg.CorrelationIDTemplate == "" {
+		return nil
+	}
+	tmpl, err := template.New("correlation_id").Option("missingkey=error").Parse(cfg.CorrelationIDTemplate)
+	if err != nil {
+		logrus.Error("Invalid correlation ID template, using fingerprint:", err)
+		return nil
+	}
+	return tmpl
+}
+
+// setCorrelationID sets the correlation_id annotation unless the alert
+// already carries one.
+func setCorrelationID(alert *Alert, tmpl *template.Template) {
+	if alert.Annotations["correlation_id"] != "" {
 		return
 	}
 
-	metrics.AlertsReceived.Inc()
-	basicProcessor := &processors.BasicProcessor{}
-	for _, alert := range alerts {
+	id := alert.Fingerprint()
+	if tmpl != nil {
+		var b strings.Builder
+		if err := tmpl.Execute(&b, alert); err != nil {
+			logrus.Error("Error rendering correlation ID, using fingerprint:", err)
+		} else {
+			id = b.String()
+		}
+	}
 
-		logrus.Info("Received alert:", alert)
-		basicProcessor.Process(alert)
+	if alert.Annotations == nil {
+		alert.Annotations = make(map[string]string)
 	}
+	alert.Annotations["correlation_id"] = id
+}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Alerts received"))
+func normalizeLabels(names []string, labels map[string]string) {
+	if len(names) == 0 {
+		names = defaultNormalizeLabels
+	}
+	for _, name := range names {
+		if value, ok := labels[name]; ok {
+			labels[name] = strings.ToLower(strings.TrimSpace(value))
+		}
+	}
 }
 
-func respondWithError(w http.ResponseWriter, statusCode int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	response := map[string]string{"error": message}
-	json.NewEncoder(w).Encode(response)
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := token.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %v, got %v", delim, token)
+	}
+	return nil
 }
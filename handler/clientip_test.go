@@ -0,0 +1,71 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrustedProxies_ClientIP(t *testing.T) {
+	proxies, err := handler.ParseTrustedProxies([]string{"10.0.0.0/8"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		expectedIP   string
+	}{
+		{"untrusted source ignores header", "203.0.113.7:4000", "198.51.100.1", "203.0.113.7"},
+		{"trusted source uses header", "10.0.0.5:4000", "198.51.100.1", "198.51.100.1"},
+		{"trusted source skips trusted hops", "10.0.0.5:4000", "198.51.100.1, 10.1.2.3", "198.51.100.1"},
+		{"trusted source stops at first untrusted hop", "10.0.0.5:4000", "192.0.2.9, 198.51.100.1", "198.51.100.1"},
+		{"trusted source without header", "10.0.0.5:4000", "", "10.0.0.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/alerts", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+
+			assert.Equal(t, tt.expectedIP, proxies.ClientIP(req))
+		})
+	}
+}
+
+func TestParseTrustedProxies_Invalid(t *testing.T) {
+	_, err := handler.ParseTrustedProxies([]string{"not-a-cidr"})
+	assert.Error(t, err)
+}
+
+func TestRequestLogger(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	proxies, err := handler.ParseTrustedProxies([]string{"10.0.0.0/8"})
+	assert.NoError(t, err)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := handler.Chain(next, handler.RequestLogger(proxies))
+
+	req := httptest.NewRequest("POST", "/alerts", nil)
+	req.RemoteAddr = "10.0.0.5:4000"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	entry := hook.LastEntry()
+	assert.NotNil(t, entry)
+	assert.Equal(t, logrus.InfoLevel, entry.Level)
+	assert.Equal(t, "198.51.100.1", entry.Data["client_ip"])
+	assert.Equal(t, "/alerts", entry.Data["path"])
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+)
+
+// Error codes returned in the "code" field of error responses. They are
+// stable so clients can branch on them instead of parsing messages.
+const (
+	ErrCodeInvalidJSON          = "invalid_json"
+	ErrCodeEmptyPayload         = "empty_payload"
+	ErrCodeInvalidEncoding      = "invalid_encoding"
+	ErrCodePayloadTooLarge      = "payload_too_large"
+	ErrCodeInvalidTimestamp     = "invalid_timestamp"
+	ErrCodeMissingAlertname     = "missing_alertname"
+	ErrCodeMethodNotAllowed     = "method_not_allowed"
+	ErrCodeOriginNotAllowed     = "origin_not_allowed"
+	ErrCodeUnauthorized         = "unauthorized"
+	ErrCodeTimeout              = "timeout"
+	ErrCodeStreamingUnsupported = "streaming_unsupported"
+	ErrCodeInvalidSilence       = "invalid_silence"
+	ErrCodeNotFound             = "not_found"
+	ErrCodeInvalidQuery         = "invalid_query"
+	ErrCodeUnavailable          = "unavailable"
+)
+
+// AlertManager retries a webhook delivery that fails with a 5xx status and
+// gives up on a 4xx. Permanent errors, where resending the same request
+// would fail the same way, therefore map to 4xx and server-side failures to
+// 5xx.
+var errorStatus = map[string]int{
+	ErrCodeInvalidJSON:          http.StatusBadRequest,
+	ErrCodeEmptyPayload:         http.StatusBadRequest,
+	ErrCodeInvalidEncoding:      http.StatusBadRequest,
+	ErrCodePayloadTooLarge:      http.StatusRequestEntityTooLarge,
+	ErrCodeInvalidTimestamp:     http.StatusBadRequest,
+	ErrCodeMissingAlertname:     http.StatusBadRequest,
+	ErrCodeMethodNotAllowed:     http.StatusMethodNotAllowed,
+	ErrCodeOriginNotAllowed:     http.StatusForbidden,
+	ErrCodeUnauthorized:         http.StatusUnauthorized,
+	ErrCodeTimeout:              http.StatusGatewayTimeout,
+	ErrCodeStreamingUnsupported: http.StatusInternalServerError,
+	ErrCodeInvalidSilence:       http.StatusBadRequest,
+	ErrCodeNotFound:             http.StatusNotFound,
+	ErrCodeInvalidQuery:         http.StatusBadRequest,
+	ErrCodeUnavailable:          http.StatusServiceUnavailable,
+}
+
+// StatusForCode returns the HTTP status sent with an error code.
+func StatusForCode(code string) int {
+	if status, ok := errorStatus[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// MethodNotAllowed answers requests whose path matched a route but whose
+// method did not, using the standard error body.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	respondWithError(w, ErrCodeMethodNotAllowed, "Method not allowed")
+}
+
+func respondWithError(w http.ResponseWriter, code, message string) {
+	respondWithJSON(w, StatusForCode(code), map[string]string{"code": code, "error": message})
+}
@@ -0,0 +1,97 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/igormishsky/prometheus-alerts-handler/config"
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCodes(t *testing.T) {
+	router := mux.NewRouter()
+	router.MethodNotAllowedHandler = http.HandlerFunc(handler.MethodNotAllowed)
+	router.Handle("/alerts", handler.NewAlertsHandler(config.AlertsConfig{
+		MaxAlertsPerRequest: 1,
+		RequireAlertname:    true,
+		StrictTimestamps:    true,
+	}, &recordingProcessor{})).Methods("POST")
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		header     map[string]string
+		statusCode int
+		code       string
+	}{
+		{"invalid json", "POST", `not json`, nil, http.StatusBadRequest, handler.ErrCodeInvalidJSON},
+		{"truncated array", "POST", `[{"labels": {"alertname": `, nil, http.StatusBadRequest, handler.ErrCodeInvalidJSON},
+		{"empty payload", "POST", ``, nil, http.StatusBadRequest, handler.ErrCodeEmptyPayload},
+		{"bad gzip", "POST", `[]`, map[string]string{"Content-Encoding": "gzip"}, http.StatusBadRequest, handler.ErrCodeInvalidEncoding},
+		{"too many alerts", "POST", `[{"labels": {"alertname": "A"}}, {"labels": {"alertname": "B"}}]`, nil, http.StatusRequestEntityTooLarge, handler.ErrCodePayloadTooLarge},
+		{"bad timestamp", "POST", `[{"labels": {"alertname": "A"}, "startsAt": "yesterday"}]`, nil, http.StatusBadRequest, handler.ErrCodeInvalidTimestamp},
+		{"missing alertname", "POST", `[{"labels": {"severity": "info"}}]`, nil, http.StatusBadRequest, handler.ErrCodeMissingAlertname},
+		{"wrong method", "PUT", `[]`, nil, http.StatusMethodNotAllowed, handler.ErrCodeMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/alerts", strings.NewReader(tt.body))
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+			var body struct {
+				Code  string `json:"code"`
+				Error string `json:"error"`
+			}
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+			assert.Equal(t, tt.code, body.Code)
+			assert.NotEmpty(t, body.Error)
+		})
+	}
+}
+
+func TestErrorStatus_TransientVersusPermanent(t *testing.T) {
+	body := `[{"status": "firing", "labels": {"alertname": "Retry"}}]`
+
+	slow := handler.Chain(handler.NewAlertsHandler(config.AlertsConfig{}, &slowProcessor{delay: 200 * time.Millisecond}),
+		handler.RequestTimeout(20*time.Millisecond))
+	rr := httptest.NewRecorder()
+	slow.ServeHTTP(rr, httptest.NewRequest("POST", "/alerts", strings.NewReader(body)))
+	assert.Equal(t, http.StatusGatewayTimeout, rr.Code)
+
+	hub := handler.NewStreamHub()
+	hub.Close()
+	rr = httptest.NewRecorder()
+	hub.ServeHTTP(rr, httptest.NewRequest("GET", "/alerts/stream", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Contains(t, rr.Body.String(), handler.ErrCodeUnavailable)
+
+	alerts := handler.NewAlertsHandler(config.AlertsConfig{}, &recordingProcessor{})
+	for _, bad := range []string{`not json`, ``, `[{"startsAt": 1}]`} {
+		rr := httptest.NewRecorder()
+		alerts.ServeHTTP(rr, httptest.NewRequest("POST", "/alerts", strings.NewReader(bad)))
+		assert.True(t, rr.Code >= 400 && rr.Code < 500, "permanent error for %q got %d", bad, rr.Code)
+	}
+}
+
+func TestStatusForCode(t *testing.T) {
+	assert.Equal(t, http.StatusGatewayTimeout, handler.StatusForCode(handler.ErrCodeTimeout))
+	assert.Equal(t, http.StatusServiceUnavailable, handler.StatusForCode(handler.ErrCodeUnavailable))
+	assert.Equal(t, http.StatusBadRequest, handler.StatusForCode(handler.ErrCodeInvalidJSON))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, handler.StatusForCode(handler.ErrCodePayloadTooLarge))
+	assert.Equal(t, http.StatusInternalServerError, handler.StatusForCode("unknown"))
+}
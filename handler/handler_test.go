@@ -2,12 +2,22 @@ package handler_test
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/igormishsky/prometheus-alerts-handler/config"
 	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/metrics"
+	"github.com/igormishsky/prometheus-alerts-handler/redact"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -37,3 +47,468 @@ func TestAlertsHandler(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Equal(t, "Alerts received", rr.Body.String())
 }
+
+type recordingProcessor struct {
+	mu     sync.Mutex
+	alerts []handler.Alert
+}
+
+func (rp *recordingProcessor) Process(alert handler.Alert) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.alerts = append(rp.alerts, alert)
+}
+
+func (rp *recordingProcessor) count() int {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	return len(rp.alerts)
+}
+
+func TestAlertsHandler_Streaming(t *testing.T) {
+	const total = 5000
+
+	alertBytes, _ := json.Marshal(handler.Alert{
+		Status: "firing",
+		Labels: map[string]string{
+			"severity": "warning",
+		},
+	})
+
+	pr, pw := io.Pipe()
+	req, err := http.NewRequest("POST", "/alerts", pr)
+	assert.NoError(t, err)
+
+	processor := &recordingProcessor{}
+	rr := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		handler.NewAlertsHandler(config.AlertsConfig{}, processor).ServeHTTP(rr, req)
+		close(done)
+	}()
+
+	pw.Write([]byte("["))
+	for i := 0; i < total; i++ {
+		if i > 0 {
+			pw.Write([]byte(","))
+		}
+		pw.Write(alertBytes)
+
+		// Alerts must be dispatched before the request body is complete.
+		if i == total/2 {
+			assert.Eventually(t, func() bool { return processor.count() > 0 }, time.Second, time.Millisecond)
+		}
+	}
+	pw.Write([]byte("]"))
+	pw.Close()
+	<-done
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, total, processor.count())
+}
+
+func TestAlertsHandler_InvalidJSON(t *testing.T) {
+	for _, body := range []string{`{"status":"firing"}`, `[{"status":"firing"}`, `not json`} {
+		req, err := http.NewRequest("POST", "/alerts", strings.NewReader(body))
+		assert.NoError(t, err)
+
+		processor := &recordingProcessor{}
+		rr := httptest.NewRecorder()
+		handler.NewAlertsHandler(config.AlertsConfig{}, processor).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+	}
+}
+
+func TestAlertsHandler_Defaults(t *testing.T) {
+	cfg := config.AlertsConfig{
+		DefaultLabels: map[string]string{
+			"team": "platform",
+		},
+		DefaultAnnotations: map[string]string{
+			"runbook_url": "https://runbooks.example.com/default",
+		},
+	}
+
+	body := `[
+		{"status": "firing", "labels": {"alertname": "A"}},
+		{"status": "firing", "labels": {"alertname": "B", "team": "db"}, "annotations": {"runbook_url": "https://runbooks.example.com/b"}}
+	]`
+	req, err := http.NewRequest("POST", "/alerts", strings.NewReader(body))
+	assert.NoError(t, err)
+
+	processor := &recordingProcessor{}
+	rr := httptest.NewRecorder()
+	handler.NewAlertsHandler(cfg, processor).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 2, processor.count())
+
+	assert.Equal(t, "platform", processor.alerts[0].Labels["team"])
+	assert.Equal(t, "https://runbooks.example.com/default", processor.alerts[0].Annotations["runbook_url"])
+
+	assert.Equal(t, "db", processor.alerts[1].Labels["team"])
+	assert.Equal(t, "https://runbooks.example.com/b", processor.alerts[1].Annotations["runbook_url"])
+}
+
+func TestAlertsHandler_MaxAlertAge(t *testing.T) {
+	cfg := config.AlertsConfig{MaxAlertAge: time.Hour}
+
+	alerts := []handler.Alert{
+		{
+			Status:   "firing",
+			Labels:   map[string]string{"alertname": "Fresh"},
+			StartsAt: time.Now().Add(-time.Minute).Format(time.RFC3339),
+		},
+		{
+			Status:   "firing",
+			Labels:   map[string]string{"alertname": "Stale"},
+			StartsAt: time.Now().Add(-2 * time.Hour).Format(time.RFC3339),
+		},
+		{
+			Status:   "resolved",
+			Labels:   map[string]string{"alertname": "StaleResolved"},
+			StartsAt: time.Now().Add(-2 * time.Hour).Format(time.RFC3339),
+		},
+	}
+	alertsBytes, _ := json.Marshal(alerts)
+
+	req, err := http.NewRequest("POST", "/alerts", bytes.NewBuffer(alertsBytes))
+	assert.NoError(t, err)
+
+	stale := metrics.AlertsSuppressed.WithLabelValues("stale")
+	before := testutil.ToFloat64(stale)
+
+	processor := &recordingProcessor{}
+	rr := httptest.NewRecorder()
+	handler.NewAlertsHandler(cfg, processor).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 2, processor.count())
+	assert.Equal(t, "Fresh", processor.alerts[0].Labels["alertname"])
+	assert.Equal(t, "StaleResolved", processor.alerts[1].Labels["alertname"])
+	assert.Equal(t, before+1, testutil.ToFloat64(stale))
+}
+
+func TestAlertsHandler_Normalize(t *testing.T) {
+	body := `[{"status": "firing", "labels": {"alertname": "Disk", "severity": " Critical ", "team": " DB "}}]`
+
+	tests := []struct {
+		name             string
+		cfg              config.AlertsConfig
+		expectedSeverity string
+		expectedTeam     string
+	}{
+		{"disabled", config.AlertsConfig{}, " Critical ", " DB "},
+		{"default labels", config.AlertsConfig{Normalize: true}, "critical", " DB "},
+		{"custom labels", config.AlertsConfig{Normalize: true, NormalizeLabels: []string{"team"}}, " Critical ", "db"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/alerts", strings.NewReader(body))
+			assert.NoError(t, err)
+
+			processor := &recordingProcessor{}
+			rr := httptest.NewRecorder()
+			handler.NewAlertsHandler(tt.cfg, processor).ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tt.expectedSeverity, processor.alerts[0].Labels["severity"])
+			assert.Equal(t, tt.expectedTeam, processor.alerts[0].Labels["team"])
+			assert.Equal(t, "Disk", processor.alerts[0].Labels["alertname"])
+		})
+	}
+}
+
+func TestAlertsHandler_RedactsLogs(t *testing.T) {
+	redact.SetKeys([]string{"api_key"})
+	defer redact.SetKeys(nil)
+
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	body := `[{"status": "firing", "labels": {"alertname": "Leak", "api_key": "s3cr3t"}}]`
+	req, err := http.NewRequest("POST", "/alerts", strings.NewReader(body))
+	assert.NoError(t, err)
+
+	processor := &recordingProcessor{}
+	rr := httptest.NewRecorder()
+	handler.NewAlertsHandler(config.AlertsConfig{}, processor).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "s3cr3t", processor.alerts[0].Labels["api_key"], "processors must receive the real value")
+
+	assert.NotEmpty(t, hook.AllEntries())
+	for _, entry := range hook.AllEntries() {
+		assert.NotContains(t, entry.Message, "s3cr3t")
+	}
+	assert.Contains(t, hook.AllEntries()[0].Message, "api_key:***")
+}
+
+func TestAlertsHandler_Gzip(t *testing.T) {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write([]byte(`[{"status": "firing", "labels": {"alertname": "Compressed"}}]`))
+	gz.Close()
+
+	req, err := http.NewRequest("POST", "/alerts", &compressed)
+	assert.NoError(t, err)
+	req.Header.Set("Content-Encoding", "gzip")
+
+	processor := &recordingProcessor{}
+	rr := httptest.NewRecorder()
+	handler.NewAlertsHandler(config.AlertsConfig{}, processor).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 1, processor.count())
+	assert.Equal(t, "Compressed", processor.alerts[0].Labels["alertname"])
+}
+
+func TestAlertsHandler_MalformedGzip(t *testing.T) {
+	req, err := http.NewRequest("POST", "/alerts", strings.NewReader(`[{"status": "firing"}]`))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Encoding", "gzip")
+
+	processor := &recordingProcessor{}
+	rr := httptest.NewRecorder()
+	handler.NewAlertsHandler(config.AlertsConfig{}, processor).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, 0, processor.count())
+}
+
+func TestAlertsHandler_DedupInRequest(t *testing.T) {
+	body := `[
+		{"status": "firing", "labels": {"alertname": "Disk", "instance": "a"}},
+		{"status": "firing", "labels": {"instance": "a", "alertname": "Disk"}},
+		{"status": "firing", "labels": {"alertname": "Disk", "instance": "b"}},
+		{"status": "resolved", "labels": {"alertname": "Disk", "instance": "a"}}
+	]`
+
+	tests := []struct {
+		name     string
+		cfg      config.AlertsConfig
+		expected int
+	}{
+		{"disabled", config.AlertsConfig{}, 4},
+		{"enabled", config.AlertsConfig{DedupInRequest: true}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dedup := metrics.AlertsSuppressed.WithLabelValues("dedup")
+			before := testutil.ToFloat64(dedup)
+
+			req, err := http.NewRequest("POST", "/alerts", strings.NewReader(body))
+			assert.NoError(t, err)
+
+			processor := &recordingProcessor{}
+			rr := httptest.NewRecorder()
+			handler.NewAlertsHandler(tt.cfg, processor).ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tt.expected, processor.count())
+			assert.Equal(t, before+float64(4-tt.expected), testutil.ToFloat64(dedup))
+		})
+	}
+}
+
+func TestAlertsHandler_Timestamps(t *testing.T) {
+	tests := []struct {
+		name             string
+		strict           bool
+		startsAt         string
+		expectedCode     int
+		expectedStartsAt string
+	}{
+		{"valid", true, "2023-04-01T12:00:00Z", http.StatusOK, "2023-04-01T12:00:00Z"},
+		{"empty", true, "", http.StatusOK, ""},
+		{"malformed strict", true, "yesterday", http.StatusBadRequest, ""},
+		{"malformed lenient", false, "yesterday", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alertsBytes, _ := json.Marshal([]handler.Alert{{
+				Status:   "firing",
+				Labels:   map[string]string{"alertname": "Timestamps"},
+				StartsAt: tt.startsAt,
+			}})
+			req, err := http.NewRequest("POST", "/alerts", bytes.NewBuffer(alertsBytes))
+			assert.NoError(t, err)
+
+			processor := &recordingProcessor{}
+			rr := httptest.NewRecorder()
+			handler.NewAlertsHandler(config.AlertsConfig{StrictTimestamps: tt.strict}, processor).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedCode != http.StatusOK {
+				assert.Equal(t, 0, processor.count())
+				return
+			}
+			assert.Equal(t, 1, processor.count())
+			assert.Equal(t, tt.expectedStartsAt, processor.alerts[0].StartsAt)
+		})
+	}
+}
+
+func TestAlertsHandler_StrictTimestampsRejectsWholeRequest(t *testing.T) {
+	body := `[
+		{"status": "firing", "labels": {"alertname": "Valid"}, "startsAt": "2023-04-01T12:00:00Z"},
+		{"status": "firing", "labels": {"alertname": "Invalid"}, "startsAt": "yesterday"}
+	]`
+	req, err := http.NewRequest("POST", "/alerts", strings.NewReader(body))
+	assert.NoError(t, err)
+
+	processor := &recordingProcessor{}
+	rr := httptest.NewRecorder()
+	handler.NewAlertsHandler(config.AlertsConfig{StrictTimestamps: true}, processor).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, 0, processor.count())
+}
+
+func TestAlertsHandler_RequireAlertname(t *testing.T) {
+	body := `[
+		{"status": "firing", "labels": {"alertname": "Named"}},
+		{"status": "firing", "labels": {"severity": "critical"}}
+	]`
+
+	t.Run("reject", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/alerts", strings.NewReader(body))
+		assert.NoError(t, err)
+
+		processor := &recordingProcessor{}
+		rr := httptest.NewRecorder()
+		handler.NewAlertsHandler(config.AlertsConfig{RequireAlertname: true}, processor).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), "index 1")
+		assert.Equal(t, 0, processor.count())
+	})
+
+	t.Run("drop", func(t *testing.T) {
+		missing := metrics.AlertsSuppressed.WithLabelValues("missing_alertname")
+		before := testutil.ToFloat64(missing)
+
+		req, err := http.NewRequest("POST", "/alerts", strings.NewReader(body))
+		assert.NoError(t, err)
+
+		cfg := config.AlertsConfig{RequireAlertname: true, MissingAlertnamePolicy: config.MissingAlertnameDrop}
+		processor := &recordingProcessor{}
+		rr := httptest.NewRecorder()
+		handler.NewAlertsHandler(cfg, processor).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, 1, processor.count())
+		assert.Equal(t, "Named", processor.alerts[0].Labels["alertname"])
+		assert.Equal(t, before+1, testutil.ToFloat64(missing))
+	})
+
+	t.Run("default label", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/alerts", strings.NewReader(body))
+		assert.NoError(t, err)
+
+		cfg := config.AlertsConfig{RequireAlertname: true, DefaultLabels: map[string]string{"alertname": "Unnamed"}}
+		processor := &recordingProcessor{}
+		rr := httptest.NewRecorder()
+		handler.NewAlertsHandler(cfg, processor).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, 2, processor.count())
+		assert.Equal(t, "Unnamed", processor.alerts[1].Labels["alertname"])
+	})
+}
+
+func TestAlertsHandler_MaxAlertsPerRequest(t *testing.T) {
+	body := `[
+		{"status": "firing", "labels": {"alertname": "A"}},
+		{"status": "firing", "labels": {"alertname": "B"}},
+		{"status": "firing", "labels": {"alertname": "C"}}
+	]`
+
+	tests := []struct {
+		name          string
+		cfg           config.AlertsConfig
+		expectedCode  int
+		expectedCount int
+	}{
+		{"under limit", config.AlertsConfig{MaxAlertsPerRequest: 3}, http.StatusOK, 3},
+		{"over limit rejected", config.AlertsConfig{MaxAlertsPerRequest: 2}, http.StatusRequestEntityTooLarge, 0},
+		{"over limit split", config.AlertsConfig{MaxAlertsPerRequest: 2, OversizedRequestPolicy: config.OversizedSplit}, http.StatusOK, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/alerts", strings.NewReader(body))
+			assert.NoError(t, err)
+
+			processor := &recordingProcessor{}
+			rr := httptest.NewRecorder()
+			handler.NewAlertsHandler(tt.cfg, processor).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			assert.Equal(t, tt.expectedCount, processor.count())
+		})
+	}
+}
+
+func TestAlertsHandler_CorrelationID(t *testing.T) {
+	body := `[
+		{"status": "firing", "labels": {"alertname": "Disk", "instance": "a"}},
+		{"status": "resolved", "labels": {"instance": "a", "alertname": "Disk"}},
+		{"status": "firing", "labels": {"alertname": "Disk", "instance": "b"}},
+		{"status": "firing", "labels": {"alertname": "Disk"}, "annotations": {"correlation_id": "INC-42"}}
+	]`
+
+	post := func(cfg config.AlertsConfig) *recordingProcessor {
+		req, err := http.NewRequest("POST", "/alerts", strings.NewReader(body))
+		assert.NoError(t, err)
+
+		processor := &recordingProcessor{}
+		rr := httptest.NewRecorder()
+		handler.NewAlertsHandler(cfg, processor).ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		return processor
+	}
+
+	t.Run("fingerprint", func(t *testing.T) {
+		processor := post(config.AlertsConfig{CorrelationID: true})
+
+		first := processor.alerts[0].Annotations["correlation_id"]
+		assert.NotEmpty(t, first)
+		assert.Equal(t, first, processor.alerts[1].Annotations["correlation_id"])
+		assert.NotEqual(t, first, processor.alerts[2].Annotations["correlation_id"])
+		assert.Equal(t, "INC-42", processor.alerts[3].Annotations["correlation_id"])
+
+		assert.Equal(t, first, post(config.AlertsConfig{CorrelationID: true}).alerts[0].Annotations["correlation_id"])
+	})
+
+	t.Run("template", func(t *testing.T) {
+		processor := post(config.AlertsConfig{
+			CorrelationID:         true,
+			CorrelationIDTemplate: "{{.Labels.alertname}}-{{.Labels.instance}}",
+		})
+
+		assert.Equal(t, "Disk-a", processor.alerts[0].Annotations["correlation_id"])
+		assert.Equal(t, "Disk-b", processor.alerts[2].Annotations["correlation_id"])
+		assert.Equal(t, "INC-42", processor.alerts[3].Annotations["correlation_id"])
+	})
+
+	t.Run("template missing label", func(t *testing.T) {
+		processor := post(config.AlertsConfig{
+			CorrelationID:         true,
+			CorrelationIDTemplate: "{{.Labels.alertname}}-{{.Labels.team}}",
+		})
+
+		fingerprint := post(config.AlertsConfig{CorrelationID: true})
+		assert.Equal(t, fingerprint.alerts[0].Annotations["correlation_id"], processor.alerts[0].Annotations["correlation_id"])
+		assert.Equal(t, fingerprint.alerts[2].Annotations["correlation_id"], processor.alerts[2].Annotations["correlation_id"])
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		processor := post(config.AlertsConfig{})
+		assert.NotContains(t, processor.alerts[0].Annotations, "correlation_id")
+	})
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"github.com/igormishsky/prometheus-alerts-handler/config"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+)
+
+type Middleware func(http.Handler) http.Handler
+
+// Chain wraps h with the given middlewares. The first middleware is the
+// outermost one and sees the request first.
+func Chain(h http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+var (
+	defaultCORSMethods = []string{"POST", "OPTIONS"}
+	defaultCORSHeaders = []string{"Content-Type"}
+)
+
+// CORS answers preflight requests and sets CORS headers for the configured
+// origins. Requests from other origins are rejected. With no allowed origins
+// configured the middleware is a no-op.
+func CORS(cfg config.CORSConfig) Middleware {
+	methods := cfg.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	headers := cfg.AllowedHeaders
+	if len(headers) == 0 {
+		headers = defaultCORSHeaders
+	}
+
+	return func(next http.Handler) http.Handler {
+		if len(cfg.AllowedOrigins) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowOrigin, ok := matchOrigin(cfg.AllowedOrigins, origin)
+			if !ok {
+				logrus.Warn("Rejected request from disallowed origin:", origin)
+				respondWithError(w, ErrCodeOriginNotAllowed, "Origin not allowed")
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+			w.Header().Add("Vary", "Origin")
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+				w.Header().Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func matchOrigin(allowed []string, origin string) (string, bool) {
+	for _, o := range allowed {
+		if o == "*" {
+			return "*", true
+		}
+		if strings.EqualFold(o, origin) {
+			return origin, true
+		}
+	}
+	return "", false
+}
+
+// RequestLogger logs every request with its method, path and client IP, as
+// resolved through the trusted proxies.
+func RequestLogger(proxies TrustedProxies) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logrus.WithFields(logrus.Fields{
+				"method":    r.Method,
+				"path":      r.URL.Path,
+				"client_ip": proxies.ClientIP(r),
+			}).Info("Received request")
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Auth requires requests to carry the configured Basic Auth credentials or
+// bearer token. With no credentials configured the middleware is a no-op.
+func Auth(cfg config.AuthConfig) Middleware {
+	return func(next http.Handler) http.Handler {
+		if !cfg.Enabled() {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !authorized(cfg, r) {
+				if cfg.Username != "" {
+					w.Header().Set("WWW-Authenticate", `Basic realm="prometheus-alerts-handler"`)
+				}
+				respondWithError(w, ErrCodeUnauthorized, "Unauthorized")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func authorized(cfg config.AuthConfig, r *http.Request) bool {
+	if cfg.BearerToken != "" {
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			return secureEqual(strings.TrimPrefix(auth, "Bearer "), cfg.BearerToken)
+		}
+	}
+
+	if cfg.Username != "" {
+		if username, password, ok := r.BasicAuth(); ok {
+			return secureEqual(username, cfg.Username) && secureEqual(password, cfg.Password)
+		}
+	}
+
+	return false
+}
+
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
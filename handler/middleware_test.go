@@ -0,0 +1,120 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/igormishsky/prometheus-alerts-handler/config"
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCORSHandler(cfg config.CORSConfig) http.Handler {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return handler.Chain(next, handler.CORS(cfg))
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	h := newCORSHandler(config.CORSConfig{
+		AllowedOrigins: []string{"https://dashboard.example.com"},
+		AllowedMethods: []string{"POST"},
+		AllowedHeaders: []string{"Content-Type", "Authorization"},
+	})
+
+	req, err := http.NewRequest("OPTIONS", "/alerts", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Origin", "https://dashboard.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "https://dashboard.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "POST", rr.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Content-Type, Authorization", rr.Header().Get("Access-Control-Allow-Headers"))
+}
+
+func TestCORS_DisallowedOrigin(t *testing.T) {
+	h := newCORSHandler(config.CORSConfig{
+		AllowedOrigins: []string{"https://dashboard.example.com"},
+	})
+
+	req, err := http.NewRequest("POST", "/alerts", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Origin", "https://evil.example.com")
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_Disabled(t *testing.T) {
+	h := newCORSHandler(config.CORSConfig{})
+
+	req, err := http.NewRequest("POST", "/alerts", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Origin", "https://dashboard.example.com")
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestAuth(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := handler.Chain(next, handler.Auth(config.AuthConfig{
+		Username:    "prometheus",
+		Password:    "secret",
+		BearerToken: "token",
+	}))
+
+	tests := []struct {
+		name     string
+		setAuth  func(req *http.Request)
+		expected int
+	}{
+		{"no credentials", func(req *http.Request) {}, http.StatusUnauthorized},
+		{"wrong password", func(req *http.Request) { req.SetBasicAuth("prometheus", "wrong") }, http.StatusUnauthorized},
+		{"basic auth", func(req *http.Request) { req.SetBasicAuth("prometheus", "secret") }, http.StatusOK},
+		{"wrong token", func(req *http.Request) { req.Header.Set("Authorization", "Bearer wrong") }, http.StatusUnauthorized},
+		{"bearer token", func(req *http.Request) { req.Header.Set("Authorization", "Bearer token") }, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/metrics", nil)
+			assert.NoError(t, err)
+			tt.setAuth(req)
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expected, rr.Code)
+		})
+	}
+}
+
+func TestAuth_Disabled(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := handler.Chain(next, handler.Auth(config.AuthConfig{}))
+
+	req, err := http.NewRequest("GET", "/metrics", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/igormishsky/prometheus-alerts-handler/redact"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+)
+
+type processorState struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// ListProcessorsHandler reports the named sinks and whether each is enabled.
+func ListProcessorsHandler(reg *processors.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		states := make([]processorState, 0, len(reg.Sinks()))
+		for _, tp := range reg.Sinks() {
+			states = append(states, processorState{Name: tp.Name, Enabled: tp.Enabled()})
+		}
+		respondWithJSON(w, http.StatusOK, states)
+	}
+}
+
+// ToggleProcessorHandler enables or disables the sink named by the {name}
+// route variable.
+func ToggleProcessorHandler(reg *processors.Registry, enabled bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["name"]
+		tp, ok := reg.Get(name)
+		if !ok {
+			respondWithError(w, ErrCodeNotFound, "Processor not found")
+			return
+		}
+
+		if enabled {
+			tp.Enable()
+			logrus.WithField("processor", name).Info("Processor enabled")
+		} else {
+			tp.Disable()
+			logrus.WithField("processor", name).Warn("Processor disabled")
+		}
+		respondWithJSON(w, http.StatusOK, processorState{Name: name, Enabled: enabled})
+	}
+}
+
+// InjectAlertHandler decodes a single alert and delivers it only to the
+// sink named by the processor query parameter, bypassing silences, the
+// resolved guard, the sink's severity threshold and whether it is enabled.
+func InjectAlertHandler(reg *processors.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("processor")
+		if name == "" {
+			respondWithError(w, ErrCodeInvalidQuery, "Missing processor query parameter")
+			return
+		}
+		tp, ok := reg.Get(name)
+		if !ok {
+			respondWithError(w, ErrCodeNotFound, "Processor not found")
+			return
+		}
+
+		var alert Alert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			if err == io.EOF {
+				respondWithError(w, ErrCodeEmptyPayload, "Request body is empty")
+				return
+			}
+			logrus.Error("Error unmarshalling test alert:", err)
+			respondWithError(w, ErrCodeInvalidJSON, "Error unmarshalling request body")
+			return
+		}
+
+		logrus.WithField("processor", name).Info("Injecting test alert:", redact.Alert(alert))
+		tp.Inject(alert)
+		respondWithJSON(w, http.StatusOK, map[string]string{"processor": name})
+	}
+}
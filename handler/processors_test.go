@@ -0,0 +1,119 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/igormishsky/prometheus-alerts-handler/config"
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/stretchr/testify/assert"
+)
+
+func processorsRouter(reg *processors.Registry) *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/processors", handler.ListProcessorsHandler(reg)).Methods("GET")
+	router.HandleFunc("/admin/processors/{name}/enable", handler.ToggleProcessorHandler(reg, true)).Methods("POST")
+	router.HandleFunc("/admin/processors/{name}/disable", handler.ToggleProcessorHandler(reg, false)).Methods("POST")
+	router.HandleFunc("/alerts/test", handler.InjectAlertHandler(reg)).Methods("POST")
+	return router
+}
+
+func TestToggleProcessorHandler(t *testing.T) {
+	var reg processors.Registry
+	stdout, adaptiveCard, powerAutomate := &recordingProcessor{}, &recordingProcessor{}, &recordingProcessor{}
+	dispatch := processors.MultiProcessor{
+		reg.Register("stdout", stdout, stdout),
+		reg.Register("adaptivecard", adaptiveCard, adaptiveCard),
+		reg.Register("powerautomate", powerAutomate, powerAutomate),
+	}
+	router := processorsRouter(&reg)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/admin/processors/adaptivecard/disable", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	body := `[{"status": "firing", "labels": {"alertname": "ToggleTest"}}]`
+	rr = httptest.NewRecorder()
+	handler.NewAlertsHandler(config.AlertsConfig{}, dispatch).ServeHTTP(rr, httptest.NewRequest("POST", "/alerts", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	assert.Equal(t, 1, stdout.count())
+	assert.Equal(t, 0, adaptiveCard.count())
+	assert.Equal(t, 1, powerAutomate.count())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/processors", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var states []struct {
+		Name    string `json:"name"`
+		Enabled bool   `json:"enabled"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &states))
+	assert.Len(t, states, 3)
+	assert.Equal(t, "adaptivecard", states[1].Name)
+	assert.False(t, states[1].Enabled)
+	assert.True(t, states[0].Enabled)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/admin/processors/adaptivecard/enable", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	tp, _ := reg.Get("adaptivecard")
+	assert.True(t, tp.Enabled())
+}
+
+func TestToggleProcessorHandler_UnknownProcessor(t *testing.T) {
+	rr := httptest.NewRecorder()
+	processorsRouter(&processors.Registry{}).ServeHTTP(rr, httptest.NewRequest("POST", "/admin/processors/pagerduty/disable", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestInjectAlertHandler(t *testing.T) {
+	var reg processors.Registry
+	stdout, powerAutomate := &recordingProcessor{}, &recordingProcessor{}
+	reg.Register("stdout", stdout, stdout)
+	critical, err := processors.NewSeverityProcessor("critical", powerAutomate)
+	assert.NoError(t, err)
+	reg.Register("powerautomate", critical, powerAutomate).Disable()
+	router := processorsRouter(&reg)
+
+	body := `{"status": "firing", "labels": {"alertname": "InjectTest", "severity": "info"}}`
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/alerts/test?processor=powerautomate", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	assert.Equal(t, 0, stdout.count())
+	assert.Equal(t, 1, powerAutomate.count())
+	assert.Equal(t, "InjectTest", powerAutomate.alerts[0].Labels["alertname"])
+}
+
+func TestInjectAlertHandler_Errors(t *testing.T) {
+	var reg processors.Registry
+	rec := &recordingProcessor{}
+	reg.Register("stdout", rec, rec)
+	router := processorsRouter(&reg)
+
+	tests := []struct {
+		name     string
+		query    string
+		body     string
+		expected int
+	}{
+		{"missing processor", "", `{"labels": {"alertname": "X"}}`, http.StatusBadRequest},
+		{"unknown processor", "?processor=pagerduty", `{"labels": {"alertname": "X"}}`, http.StatusNotFound},
+		{"invalid json", "?processor=stdout", `{`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest("POST", "/alerts/test"+tt.query, strings.NewReader(tt.body)))
+			assert.Equal(t, tt.expected, rr.Code)
+		})
+	}
+	assert.Equal(t, 0, rec.count())
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/igormishsky/prometheus-alerts-handler/metrics"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// RequestMetrics counts requests and observes their latency. It wraps the
+// whole router rather than being installed with Use, so requests that no
+// route handles (405, 404, CORS rejections) are counted too. Requests are
+// labelled by the path template of the route they were aimed at, or
+// "unmatched", so label cardinality stays bounded.
+func RequestMetrics(router *mux.Router) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path := routeTemplate(router, r)
+
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			start := time.Now()
+			next.ServeHTTP(sw, r)
+
+			metrics.HTTPRequestDuration.WithLabelValues(path, r.Method).Observe(time.Since(start).Seconds())
+			metrics.HTTPRequests.WithLabelValues(path, r.Method, strconv.Itoa(sw.status)).Inc()
+		})
+	}
+}
+
+// routeTemplate returns the path template of the route matching r. For a
+// method mismatch, mux reports no route, so the longest template whose path
+// pattern matches is used instead.
+func routeTemplate(router *mux.Router, r *http.Request) string {
+	var match mux.RouteMatch
+	if router.Match(r, &match) && match.Route != nil {
+		if tmpl, err := match.Route.GetPathTemplate(); err == nil {
+			return tmpl
+		}
+	}
+
+	if match.MatchErr != mux.ErrMethodMismatch {
+		return "unmatched"
+	}
+
+	best := "unmatched"
+	router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		tmpl, err := route.GetPathTemplate()
+		if err != nil || best != "unmatched" && len(tmpl) <= len(best) {
+			return nil
+		}
+		pattern, err := route.GetPathRegexp()
+		if err != nil {
+			return nil
+		}
+		if ok, _ := regexp.MatchString(pattern, r.URL.Path); ok {
+			best = tmpl
+		}
+		return nil
+	})
+	return best
+}
+
+// statusWriter records the status code written through it. It passes Flush
+// through so streaming endpoints keep working.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (sw *statusWriter) WriteHeader(code int) {
+	if !sw.wroteHeader {
+		sw.status = code
+		sw.wroteHeader = true
+	}
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	sw.wroteHeader = true
+	return sw.ResponseWriter.Write(b)
+}
+
+func (sw *statusWriter) Flush() {
+	if f, ok := sw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,67 @@
+package handler_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/igormishsky/prometheus-alerts-handler/config"
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestMetrics(t *testing.T) {
+	router := mux.NewRouter()
+	router.MethodNotAllowedHandler = http.HandlerFunc(handler.MethodNotAllowed)
+	router.Handle("/alerts", handler.NewAlertsHandler(config.AlertsConfig{}, &recordingProcessor{})).Methods("POST")
+	h := handler.Chain(router, handler.RequestMetrics(router))
+
+	ok := metrics.HTTPRequests.WithLabelValues("/alerts", "POST", "200")
+	bad := metrics.HTTPRequests.WithLabelValues("/alerts", "POST", "400")
+	wrongMethod := metrics.HTTPRequests.WithLabelValues("/alerts", "GET", "405")
+	notFound := metrics.HTTPRequests.WithLabelValues("unmatched", "GET", "404")
+	okBefore, badBefore := testutil.ToFloat64(ok), testutil.ToFloat64(bad)
+	wrongMethodBefore, notFoundBefore := testutil.ToFloat64(wrongMethod), testutil.ToFloat64(notFound)
+
+	for _, body := range []string{`[{"labels": {"alertname": "RED"}}]`, `[{"labels": {"alertname": "RED"}}]`, `not json`} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/alerts", strings.NewReader(body)))
+	}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/alerts", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/no-such-path", nil))
+
+	assert.Equal(t, okBefore+2, testutil.ToFloat64(ok))
+	assert.Equal(t, badBefore+1, testutil.ToFloat64(bad))
+	assert.Equal(t, wrongMethodBefore+1, testutil.ToFloat64(wrongMethod))
+	assert.Equal(t, notFoundBefore+1, testutil.ToFloat64(notFound))
+
+	rr := httptest.NewRecorder()
+	metrics.GetHandler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	scraped, _ := ioutil.ReadAll(rr.Body)
+	out := string(scraped)
+
+	assert.Contains(t, out, `prometheus_alerts_handler_http_requests_total{method="POST",path="/alerts",status="200"}`)
+	assert.Contains(t, out, `prometheus_alerts_handler_http_requests_total{method="POST",path="/alerts",status="400"}`)
+	assert.Contains(t, out, `prometheus_alerts_handler_http_request_duration_seconds_count{method="POST",path="/alerts"}`)
+}
+
+func TestRequestMetrics_PreservesFlusher(t *testing.T) {
+	hub := handler.NewStreamHub()
+	router := mux.NewRouter()
+	router.Handle("/alerts/stream", hub).Methods("GET")
+
+	server := httptest.NewServer(handler.Chain(router, handler.RequestMetrics(router)))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/alerts/stream")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+}
@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"github.com/igormishsky/prometheus-alerts-handler/history"
+	"net/http"
+)
+
+// SearchHandler lists retained alerts matching the label query parameter
+// and, when given, its value, e.g. ?label=severity&value=critical.
+func SearchHandler(store *history.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		label := r.URL.Query().Get("label")
+		if label == "" {
+			respondWithError(w, ErrCodeInvalidQuery, "Missing label query parameter")
+			return
+		}
+		respondWithJSON(w, http.StatusOK, store.Search(label, r.URL.Query().Get("value")))
+	}
+}
@@ -0,0 +1,33 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/history"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchHandler(t *testing.T) {
+	store := history.New(time.Hour, 0)
+	store.Process(handler.Alert{Status: "firing", Labels: map[string]string{"alertname": "DiskFull", "severity": "critical"}})
+	store.Process(handler.Alert{Status: "firing", Labels: map[string]string{"alertname": "HighLatency", "severity": "warning"}})
+
+	rr := httptest.NewRecorder()
+	handler.SearchHandler(store).ServeHTTP(rr, httptest.NewRequest("GET", "/alerts/search?label=severity&value=critical", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var entries []history.Entry
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &entries))
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "DiskFull", entries[0].Alert.Labels["alertname"])
+	assert.False(t, entries[0].ReceivedAt.IsZero())
+
+	rr = httptest.NewRecorder()
+	handler.SearchHandler(store).ServeHTTP(rr, httptest.NewRequest("GET", "/alerts/search", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/igormishsky/prometheus-alerts-handler/silences"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+)
+
+type silenceRequest struct {
+	Matchers  map[string]string `json:"matchers"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+	Duration  string            `json:"duration"`
+	Comment   string            `json:"comment"`
+}
+
+// CreateSilenceHandler adds a silence. The expiry is taken from expiresAt,
+// or from duration (e.g. "2h") relative to now when expiresAt is unset.
+func CreateSilenceHandler(store *silences.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req silenceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logrus.Error("Error decoding silence:", err)
+			respondWithError(w, ErrCodeInvalidSilence, "Invalid silence")
+			return
+		}
+
+		expiresAt := req.ExpiresAt
+		if expiresAt.IsZero() && req.Duration != "" {
+			d, err := time.ParseDuration(req.Duration)
+			if err != nil {
+				respondWithError(w, ErrCodeInvalidSilence, "Invalid silence duration")
+				return
+			}
+			expiresAt = time.Now().Add(d)
+		}
+
+		s, err := store.Add(silences.Silence{Matchers: req.Matchers, ExpiresAt: expiresAt, Comment: req.Comment})
+		if err != nil {
+			respondWithError(w, ErrCodeInvalidSilence, err.Error())
+			return
+		}
+		logrus.WithField("id", s.ID).Info("Silence created")
+		respondWithJSON(w, http.StatusCreated, s)
+	}
+}
+
+func ListSilencesHandler(store *silences.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		respondWithJSON(w, http.StatusOK, store.List())
+	}
+}
+
+// DeleteSilenceHandler removes the silence named by the {id} route variable.
+func DeleteSilenceHandler(store *silences.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+		if !store.Delete(id) {
+			respondWithError(w, ErrCodeNotFound, "Silence not found")
+			return
+		}
+		logrus.WithField("id", id).Info("Silence deleted")
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
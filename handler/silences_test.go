@@ -0,0 +1,95 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/igormishsky/prometheus-alerts-handler/config"
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/metrics"
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/igormishsky/prometheus-alerts-handler/silences"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func silencesRouter(store *silences.Store) *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/silences", handler.CreateSilenceHandler(store)).Methods("POST")
+	router.HandleFunc("/silences", handler.ListSilencesHandler(store)).Methods("GET")
+	router.HandleFunc("/silences/{id}", handler.DeleteSilenceHandler(store)).Methods("DELETE")
+	return router
+}
+
+func TestSilences_SuppressUntilExpiry(t *testing.T) {
+	store := silences.NewStore()
+	router := silencesRouter(store)
+	rec := &recordingProcessor{}
+	alerts := handler.NewAlertsHandler(config.AlertsConfig{}, &processors.SilencingProcessor{Next: rec, Silences: store})
+	post := func() {
+		body := `[{"status": "firing", "labels": {"alertname": "Silenced", "env": "staging"}}]`
+		rr := httptest.NewRecorder()
+		alerts.ServeHTTP(rr, httptest.NewRequest("POST", "/alerts", strings.NewReader(body)))
+		assert.Equal(t, http.StatusOK, rr.Code)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/silences",
+		strings.NewReader(`{"matchers": {"env": "staging"}, "duration": "200ms", "comment": "deploy"}`)))
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var created silences.Silence
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, "deploy", created.Comment)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/silences", nil))
+	var listed []silences.Silence
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &listed))
+	assert.Len(t, listed, 1)
+
+	before := testutil.ToFloat64(metrics.AlertsSuppressed.WithLabelValues("silenced"))
+	post()
+	assert.Equal(t, 0, rec.count())
+	assert.Equal(t, before+1, testutil.ToFloat64(metrics.AlertsSuppressed.WithLabelValues("silenced")))
+
+	time.Sleep(250 * time.Millisecond)
+	post()
+	assert.Equal(t, 1, rec.count())
+}
+
+func TestSilences_Delete(t *testing.T) {
+	store := silences.NewStore()
+	router := silencesRouter(store)
+	s, err := store.Add(silences.Silence{Matchers: map[string]string{"alertname": "X"}, ExpiresAt: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("DELETE", "/silences/"+s.ID, nil))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("DELETE", "/silences/"+s.ID, nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestSilences_InvalidRequest(t *testing.T) {
+	router := silencesRouter(silences.NewStore())
+
+	for _, body := range []string{
+		`not json`,
+		`{"matchers": {}, "duration": "1h"}`,
+		`{"matchers": {"alertname": "X"}, "duration": "soon"}`,
+		`{"matchers": {"alertname": "X"}}`,
+	} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("POST", "/silences", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+	}
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/igormishsky/prometheus-alerts-handler/redact"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"sync"
+)
+
+const streamBufferSize = 64
+
+// StreamHub fans processed alerts out to Server-Sent Events subscribers. It
+// implements processors.AlertProcessor so it can be dispatched to like any
+// other processor, and http.Handler to serve the event stream.
+type StreamHub struct {
+	mu          sync.Mutex
+	subscribers map[chan []byte]struct{}
+	closed      chan struct{}
+	closeOnce   sync.Once
+}
+
+func NewStreamHub() *StreamHub {
+	return &StreamHub{
+		subscribers: make(map[chan []byte]struct{}),
+		closed:      make(chan struct{}),
+	}
+}
+
+// Close ends every open stream. http.Server.Shutdown does not cancel
+// request contexts, so without this connected subscribers would hold
+// shutdown open until its timeout. Register it with
+// http.Server.RegisterOnShutdown.
+func (h *StreamHub) Close() {
+	h.closeOnce.Do(func() { close(h.closed) })
+}
+
+// Process publishes alert to every subscriber. Subscribers that are too slow
+// to keep up miss the event rather than blocking dispatch. The configured
+// redact keys are masked, as they are in the logs.
+func (h *StreamHub) Process(alert Alert) {
+	data, err := json.Marshal(redact.Alert(alert))
+	if err != nil {
+		logrus.Error("Error marshalling alert for stream:", err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- data:
+		default:
+			logrus.Warn("Dropping alert for slow stream subscriber")
+		}
+	}
+}
+
+// Subscribers returns the number of connected stream clients.
+func (h *StreamHub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers)
+}
+
+func (h *StreamHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondWithError(w, ErrCodeStreamingUnsupported, "Streaming not supported")
+		return
+	}
+
+	select {
+	case <-h.closed:
+		respondWithError(w, ErrCodeUnavailable, "Shutting down")
+		return
+	default:
+	}
+
+	ch := h.subscribe()
+	defer h.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.closed:
+			return
+		case data := <-ch:
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *StreamHub) subscribe() chan []byte {
+	ch := make(chan []byte, streamBufferSize)
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *StreamHub) unsubscribe(ch chan []byte) {
+	h.mu.Lock()
+	delete(h.subscribers, ch)
+	h.mu.Unlock()
+}
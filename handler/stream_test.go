@@ -0,0 +1,84 @@
+package handler_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/igormishsky/prometheus-alerts-handler/config"
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/redact"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamHub(t *testing.T) {
+	hub := handler.NewStreamHub()
+
+	mux := http.NewServeMux()
+	mux.Handle("/alerts/stream", hub)
+	mux.Handle("/alerts", handler.NewAlertsHandler(config.AlertsConfig{}, hub))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/alerts/stream")
+	assert.NoError(t, err)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, ": connected\n", line)
+	reader.ReadString('\n')
+
+	body := `[{"status": "firing", "labels": {"alertname": "StreamTest"}}]`
+	postResp, err := http.Post(server.URL+"/alerts", "application/json", strings.NewReader(body))
+	assert.NoError(t, err)
+	postResp.Body.Close()
+	assert.Equal(t, http.StatusOK, postResp.StatusCode)
+
+	line, err = reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(line, "data: "))
+
+	var alert handler.Alert
+	assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &alert))
+	assert.Equal(t, "StreamTest", alert.Labels["alertname"])
+
+	resp.Body.Close()
+	assert.Eventually(t, func() bool { return hub.Subscribers() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestStreamHub_RedactsConfiguredKeys(t *testing.T) {
+	redact.SetKeys([]string{"api_key"})
+	defer redact.SetKeys(nil)
+
+	hub := handler.NewStreamHub()
+	server := httptest.NewServer(hub)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	reader.ReadString('\n')
+	reader.ReadString('\n')
+
+	hub.Process(handler.Alert{
+		Labels:      map[string]string{"alertname": "StreamTest"},
+		Annotations: map[string]string{"api_key": "s3cr3t"},
+	})
+
+	line, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.NotContains(t, line, "s3cr3t")
+
+	var alert handler.Alert
+	assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &alert))
+	assert.Equal(t, redact.Mask, alert.Annotations["api_key"])
+	assert.Equal(t, "StreamTest", alert.Labels["alertname"])
+}
@@ -0,0 +1,70 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/igormishsky/prometheus-alerts-handler/config"
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+type slowProcessor struct {
+	delay     time.Duration
+	delivered int32
+}
+
+func (sp *slowProcessor) Process(alert handler.Alert) {
+	time.Sleep(sp.delay)
+	atomic.AddInt32(&sp.delivered, 1)
+}
+
+func TestRequestTimeout(t *testing.T) {
+	tests := []struct {
+		name         string
+		delay        time.Duration
+		expectedCode int
+		expectedBody string
+	}{
+		{"within deadline", 0, http.StatusOK, "Alerts received"},
+		{"exceeds deadline", 500 * time.Millisecond, http.StatusGatewayTimeout, "deadline"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := handler.NewAlertsHandler(config.AlertsConfig{}, &slowProcessor{delay: tt.delay})
+			h := handler.Chain(alerts, handler.RequestTimeout(50*time.Millisecond))
+
+			body := `[{"status": "firing", "labels": {"alertname": "Slow"}}]`
+			req := httptest.NewRequest("POST", "/alerts", strings.NewReader(body))
+			rr := httptest.NewRecorder()
+
+			start := time.Now()
+			h.ServeHTTP(rr, req)
+
+			assert.Less(t, int64(time.Since(start)), int64(400*time.Millisecond))
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestRequestTimeout_StopsDispatchAfterDeadline(t *testing.T) {
+	processor := &slowProcessor{delay: 30 * time.Millisecond}
+	alerts := handler.NewAlertsHandler(config.AlertsConfig{}, processor)
+	h := handler.Chain(alerts, handler.RequestTimeout(50*time.Millisecond))
+
+	body := "[" + strings.TrimSuffix(strings.Repeat(`{"status": "firing", "labels": {"alertname": "Slow"}},`, 10), ",") + "]"
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("POST", "/alerts", strings.NewReader(body)))
+	assert.Equal(t, handler.StatusForCode(handler.ErrCodeTimeout), rr.Code)
+
+	delivered := atomic.LoadInt32(&processor.delivered)
+	time.Sleep(400 * time.Millisecond)
+	assert.Less(t, atomic.LoadInt32(&processor.delivered), int32(10))
+	assert.LessOrEqual(t, atomic.LoadInt32(&processor.delivered), delivered+1, "at most the in-flight alert may finish after the deadline")
+}
@@ -0,0 +1,69 @@
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"github.com/igormishsky/prometheus-alerts-handler/config"
+	"github.com/igormishsky/prometheus-alerts-handler/metrics"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+)
+
+// Heartbeat periodically pings a URL so external monitoring knows the
+// handler is alive.
+type Heartbeat struct {
+	url      string
+	interval time.Duration
+	client   *http.Client
+}
+
+func New(cfg config.HeartbeatConfig) *Heartbeat {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &Heartbeat{
+		url:      cfg.URL,
+		interval: interval,
+		client:   &http.Client{Timeout: interval},
+	}
+}
+
+// Run pings immediately and then once per interval until ctx is done.
+func (h *Heartbeat) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := h.ping(ctx); err != nil {
+			logrus.Error("Heartbeat ping failed:", err)
+			metrics.HeartbeatFailures.Inc()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Heartbeat) ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", h.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
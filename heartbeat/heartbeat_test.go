@@ -0,0 +1,55 @@
+package heartbeat_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/igormishsky/prometheus-alerts-handler/config"
+	"github.com/igormishsky/prometheus-alerts-handler/heartbeat"
+	"github.com/igormishsky/prometheus-alerts-handler/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func runHeartbeat(t *testing.T, status int, duration time.Duration) int32 {
+	var pings int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&pings, 1)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	hb := heartbeat.New(config.HeartbeatConfig{
+		URL:      server.URL,
+		Interval: 20 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+	hb.Run(ctx)
+
+	return atomic.LoadInt32(&pings)
+}
+
+func TestHeartbeat_Run(t *testing.T) {
+	before := testutil.ToFloat64(metrics.HeartbeatFailures)
+
+	pings := runHeartbeat(t, http.StatusOK, 110*time.Millisecond)
+
+	assert.GreaterOrEqual(t, pings, int32(4))
+	assert.LessOrEqual(t, pings, int32(7))
+	assert.Equal(t, before, testutil.ToFloat64(metrics.HeartbeatFailures))
+}
+
+func TestHeartbeat_Failure(t *testing.T) {
+	before := testutil.ToFloat64(metrics.HeartbeatFailures)
+
+	pings := runHeartbeat(t, http.StatusInternalServerError, 50*time.Millisecond)
+
+	assert.GreaterOrEqual(t, pings, int32(1))
+	assert.GreaterOrEqual(t, testutil.ToFloat64(metrics.HeartbeatFailures), before+float64(pings)-1)
+}
@@ -0,0 +1,72 @@
+package history
+
+import (
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"sync"
+	"time"
+)
+
+// Entry is a retained alert and the time it was recorded.
+type Entry struct {
+	Alert      types.Alert `json:"alert"`
+	ReceivedAt time.Time   `json:"receivedAt"`
+}
+
+// Store keeps dispatched alerts in memory for a retention period so they
+// can be searched by label. When maxEntries is positive the oldest entries
+// are also dropped once it is exceeded. Store is an AlertProcessor.
+type Store struct {
+	retention  time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func New(retention time.Duration, maxEntries int) *Store {
+	return &Store{retention: retention, maxEntries: maxEntries}
+}
+
+func (s *Store) Process(alert types.Alert) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, Entry{Alert: alert, ReceivedAt: now})
+	s.evict(now)
+}
+
+// Search returns retained alerts carrying label, oldest first. When value
+// is non-empty the label must also equal it.
+func (s *Store) Search(label, value string) []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evict(time.Now())
+
+	matches := []Entry{}
+	for _, e := range s.entries {
+		v, ok := e.Alert.Labels[label]
+		if ok && (value == "" || v == value) {
+			matches = append(matches, e)
+		}
+	}
+	return matches
+}
+
+// evict drops entries past retention or beyond maxEntries. Entries are kept
+// in insertion order, so both are trimmed from the front by reslicing;
+// append reallocates, compacting the slice, once the capacity runs out, so
+// eviction stays amortized O(1) per alert. s.mu must be held.
+func (s *Store) evict(now time.Time) {
+	drop := 0
+	for drop < len(s.entries) && now.Sub(s.entries[drop].ReceivedAt) > s.retention {
+		drop++
+	}
+	if s.maxEntries > 0 && len(s.entries)-drop > s.maxEntries {
+		drop = len(s.entries) - s.maxEntries
+	}
+	for i := 0; i < drop; i++ {
+		s.entries[i] = Entry{}
+	}
+	s.entries = s.entries[drop:]
+}
@@ -0,0 +1,66 @@
+package history_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/igormishsky/prometheus-alerts-handler/history"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func alert(name, severity string) types.Alert {
+	return types.Alert{Status: "firing", Labels: map[string]string{"alertname": name, "severity": severity}}
+}
+
+func TestStore_Search(t *testing.T) {
+	s := history.New(time.Hour, 0)
+	s.Process(alert("DiskFull", "critical"))
+	s.Process(alert("HighLatency", "warning"))
+	s.Process(alert("NodeDown", "critical"))
+	s.Process(types.Alert{Labels: map[string]string{"alertname": "NoSeverity"}})
+
+	critical := s.Search("severity", "critical")
+	assert.Len(t, critical, 2)
+	assert.Equal(t, "DiskFull", critical[0].Alert.Labels["alertname"])
+	assert.Equal(t, "NodeDown", critical[1].Alert.Labels["alertname"])
+
+	assert.Len(t, s.Search("severity", ""), 3)
+	assert.Empty(t, s.Search("severity", "info"))
+	assert.Empty(t, s.Search("team", ""))
+}
+
+func TestStore_RetentionEviction(t *testing.T) {
+	s := history.New(100*time.Millisecond, 0)
+	s.Process(alert("Old", "critical"))
+	time.Sleep(150 * time.Millisecond)
+	s.Process(alert("New", "critical"))
+
+	matches := s.Search("severity", "critical")
+	assert.Len(t, matches, 1)
+	assert.Equal(t, "New", matches[0].Alert.Labels["alertname"])
+}
+
+func TestStore_MaxEntries(t *testing.T) {
+	s := history.New(time.Hour, 2)
+	s.Process(alert("First", "critical"))
+	s.Process(alert("Second", "critical"))
+	s.Process(alert("Third", "critical"))
+
+	matches := s.Search("severity", "critical")
+	assert.Len(t, matches, 2)
+	assert.Equal(t, "Second", matches[0].Alert.Labels["alertname"])
+}
+
+func TestStore_MaxEntriesKeepsNewest(t *testing.T) {
+	s := history.New(time.Hour, 10)
+	for i := 0; i < 1000; i++ {
+		s.Process(types.Alert{Labels: map[string]string{"alertname": "Flood", "seq": strconv.Itoa(i)}})
+	}
+
+	matches := s.Search("alertname", "Flood")
+	assert.Len(t, matches, 10)
+	assert.Equal(t, "990", matches[0].Alert.Labels["seq"])
+	assert.Equal(t, "999", matches[9].Alert.Labels["seq"])
+}
@@ -1,18 +1,239 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"github.com/gorilla/mux"
+	"github.com/igormishsky/prometheus-alerts-handler/config"
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/heartbeat"
 	"github.com/igormishsky/prometheus-alerts-handler/metrics"
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/igormishsky/prometheus-alerts-handler/redact"
+	"github.com/igormishsky/prometheus-alerts-handler/replay"
 	"github.com/sirupsen/logrus"
 	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 )
 
 func main() {
 	logrus.SetFormatter(&logrus.JSONFormatter{})
 	logrus.SetLevel(logrus.InfoLevel)
+
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		runReplay(os.Args[2:])
+		return
+	}
+
 	fmt.Println("Prometheus Alerts Handler")
-	metricsRouter := mux.NewRouter()
-	metricsRouter.Handle("/metrics", metrics.GetHandler())
-	go http.ListenAndServe(":2112", metricsRouter)
+
+	cfg, err := config.LoadConfig(os.Getenv("CONFIG_PATH"))
+	if err != nil {
+		logrus.Fatal("Error loading config:", err)
+	}
+	redact.SetKeys(cfg.Logging.RedactKeys)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if cfg.Heartbeat.URL != "" {
+		go heartbeat.New(cfg.Heartbeat).Run(ctx)
+	}
+
+	trustedProxies, err := handler.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	if err != nil {
+		logrus.Fatal("Error parsing trusted proxies:", err)
+	}
+
+	pl, err := newPipeline(cfg)
+	if err != nil {
+		logrus.Fatal("Error building processor pipeline:", err)
+	}
+	if pl.enricher != nil {
+		go reloadOnSIGHUP(ctx, pl.enricher)
+	}
+
+	alertsRouter := newAlertsRouter(cfg, pl)
+
+	metricsServer := newServer(cfg.Server.MetricsAddress, newMetricsRouter(cfg.Server), cfg.Server)
+	alertsServer := newServer(cfg.Server.Address, handler.Chain(alertsRouter,
+		handler.RequestMetrics(alertsRouter),
+		handler.RequestLogger(trustedProxies),
+	), cfg.Server)
+	alertsServer.RegisterOnShutdown(pl.streamHub.Close)
+
+	go serve(metricsServer)
+	go serve(alertsServer)
+	logrus.Info("Listening for alerts on ", cfg.Server.Address)
+
+	<-ctx.Done()
+	logrus.Info("Shutting down")
+	shutdownServers(cfg.Server.ShutdownTimeout, alertsServer, metricsServer)
+}
+
+// newServer returns an http.Server for addr carrying the configured
+// connection timeouts.
+func newServer(addr string, h http.Handler, cfg config.ServerConfig) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           h,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+func reloadOnSIGHUP(ctx context.Context, enricher *processors.EnrichProcessor) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			if err := enricher.Reload(); err != nil {
+				logrus.Error("Error reloading enrichment mapping, keeping previous one:", err)
+				continue
+			}
+			logrus.Info("Reloaded enrichment mapping")
+		}
+	}
+}
+
+func serve(srv *http.Server) {
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logrus.Fatal("Error serving on ", srv.Addr, ": ", err)
+	}
+}
+
+// shutdownServers gracefully shuts the servers down, waiting at most timeout
+// for in-flight requests before closing any remaining connections.
+func shutdownServers(timeout time.Duration, servers ...*http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, srv := range servers {
+		wg.Add(1)
+		go func(srv *http.Server) {
+			defer wg.Done()
+			if err := srv.Shutdown(ctx); err != nil {
+				logrus.Warn("Forcing close of ", srv.Addr, " after shutdown timeout: ", err)
+				srv.Close()
+			}
+		}(srv)
+	}
+	wg.Wait()
+}
+
+// newAlertsRouter mounts the alert and health routes and, when admin_auth
+// is configured, the stream and operator routes behind it. CORS applies
+// only to /alerts and /alerts/stream, the routes browsers call; preflight
+// requests to them are answered without credentials.
+func newAlertsRouter(cfg *config.Config, pl *pipeline) *mux.Router {
+	router := mux.NewRouter()
+	router.MethodNotAllowedHandler = http.HandlerFunc(handler.MethodNotAllowed)
+	cors := handler.CORS(cfg.Server.CORS)
+	preflight := handler.Chain(router.MethodNotAllowedHandler, cors)
+
+	router.Handle("/alerts", handler.Chain(handler.NewAlertsHandler(cfg.Alerts, pl.processor),
+		cors,
+		handler.RequestTimeout(cfg.Server.RequestTimeout),
+	)).Methods("POST")
+	router.Handle("/alerts", preflight).Methods("OPTIONS")
+	router.HandleFunc("/health", handler.HealthHandler(pl.processor)).Methods("GET")
+
+	if !cfg.Server.AdminAuth.Enabled() {
+		logrus.Info("Admin, processor, silence, stream, test and search endpoints disabled: server.admin_auth is not configured")
+		return router
+	}
+	auth := handler.Auth(cfg.Server.AdminAuth)
+
+	router.Handle("/alerts/stream", handler.Chain(pl.streamHub, cors, auth)).Methods("GET")
+	router.Handle("/alerts/stream", preflight).Methods("OPTIONS")
+
+	adminRouter := router.PathPrefix("/admin").Subrouter()
+	adminRouter.Use(mux.MiddlewareFunc(auth))
+	adminRouter.HandleFunc("/pause", handler.PauseHandler(pl.processor)).Methods("POST")
+	adminRouter.HandleFunc("/resume", handler.ResumeHandler(pl.processor)).Methods("POST")
+	adminRouter.HandleFunc("/processors/{name}/enable", handler.ToggleProcessorHandler(pl.sinks, true)).Methods("POST")
+	adminRouter.HandleFunc("/processors/{name}/disable", handler.ToggleProcessorHandler(pl.sinks, false)).Methods("POST")
+	router.Handle("/processors", handler.Chain(handler.ListProcessorsHandler(pl.sinks), auth)).Methods("GET")
+	router.Handle("/alerts/test", handler.Chain(handler.InjectAlertHandler(pl.sinks), auth)).Methods("POST")
+
+	silencesRouter := router.PathPrefix("/silences").Subrouter()
+	silencesRouter.Use(mux.MiddlewareFunc(auth))
+	silencesRouter.HandleFunc("", handler.CreateSilenceHandler(pl.silences)).Methods("POST")
+	silencesRouter.HandleFunc("", handler.ListSilencesHandler(pl.silences)).Methods("GET")
+	silencesRouter.HandleFunc("/{id}", handler.DeleteSilenceHandler(pl.silences)).Methods("DELETE")
+
+	if pl.history != nil {
+		router.Handle("/alerts/search", handler.Chain(handler.SearchHandler(pl.history), auth)).Methods("GET")
+	}
+
+	return router
+}
+
+// newMetricsRouter serves the metrics handler on the configured path and,
+// when enabled, the pprof handlers under /debug/pprof/.
+func newMetricsRouter(cfg config.ServerConfig) http.Handler {
+	router := mux.NewRouter()
+	router.Handle(cfg.MetricsPath, metrics.GetHandler())
+
+	if cfg.PprofEnabled {
+		router.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		router.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		router.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		router.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		router.PathPrefix("/debug/pprof/").HandlerFunc(pprof.Index)
+	}
+
+	return handler.Chain(router,
+		handler.Auth(cfg.MetricsAuth),
+	)
+}
+
+// runReplay feeds alerts from a file through the pipeline configured by
+// CONFIG_PATH, so new sinks can be tried against recorded traffic.
+func runReplay(args []string) {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	rate := fs.Float64("rate", 0, "maximum number of alerts replayed per second (0 means unlimited)")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		logrus.Fatal("Usage: prometheus-alerts-handler replay [--rate N] <file>")
+	}
+
+	cfg, err := config.LoadConfig(os.Getenv("CONFIG_PATH"))
+	if err != nil {
+		logrus.Fatal("Error loading config:", err)
+	}
+	redact.SetKeys(cfg.Logging.RedactKeys)
+
+	pl, err := newPipeline(cfg)
+	if err != nil {
+		logrus.Fatal("Error building processor pipeline:", err)
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		logrus.Fatal("Error opening replay file:", err)
+	}
+	defer f.Close()
+
+	count, err := replay.Replay(f, pl.processor, *rate)
+	if err != nil {
+		logrus.Fatal("Error replaying alerts:", err)
+	}
+
+	logrus.Info("Replayed alerts: ", count)
 }
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/igormishsky/prometheus-alerts-handler/config"
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMetricsRouter_Pprof(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		expected int
+	}{
+		{"enabled", true, http.StatusOK},
+		{"disabled", false, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default().Server
+			cfg.PprofEnabled = tt.enabled
+
+			req, err := http.NewRequest("GET", "/debug/pprof/", nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			newMetricsRouter(cfg).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expected, rr.Code)
+		})
+	}
+}
+
+func TestNewMetricsRouter_MetricsPath(t *testing.T) {
+	cfg := config.Default().Server
+	cfg.MetricsPath = "/internal/metrics"
+	router := newMetricsRouter(cfg)
+
+	tests := []struct {
+		path     string
+		expected int
+	}{
+		{"/internal/metrics", http.StatusOK},
+		{"/metrics", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		assert.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, tt.expected, rr.Code, tt.path)
+	}
+}
+
+// startSlowServer serves requests that take delay to complete and returns
+// the server and its URL.
+func startSlowServer(t *testing.T, delay time.Duration, started chan<- struct{}) (*http.Server, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		time.Sleep(delay)
+		w.WriteHeader(http.StatusOK)
+	})}
+	go srv.Serve(listener)
+
+	return srv, "http://" + listener.Addr().String()
+}
+
+func TestShutdownServers_WaitsForInFlightRequests(t *testing.T) {
+	started := make(chan struct{}, 1)
+	srv, url := startSlowServer(t, 100*time.Millisecond, started)
+
+	result := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			result <- 0
+			return
+		}
+		resp.Body.Close()
+		result <- resp.StatusCode
+	}()
+	<-started
+
+	shutdownServers(time.Second, srv)
+
+	assert.Equal(t, http.StatusOK, <-result)
+}
+
+func TestShutdownServers_Timeout(t *testing.T) {
+	started := make(chan struct{}, 1)
+	srv, url := startSlowServer(t, 5*time.Second, started)
+
+	go http.Get(url)
+	<-started
+
+	start := time.Now()
+	shutdownServers(100*time.Millisecond, srv)
+
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+}
+
+func TestNewServer_Timeouts(t *testing.T) {
+	cfg := config.Default().Server
+	srv := newServer(":0", http.NotFoundHandler(), cfg)
+	assert.Equal(t, 30*time.Second, srv.ReadTimeout)
+	assert.Equal(t, 10*time.Second, srv.ReadHeaderTimeout)
+	assert.Equal(t, time.Duration(0), srv.WriteTimeout)
+	assert.Equal(t, 2*time.Minute, srv.IdleTimeout)
+
+	cfg.ReadTimeout = 5 * time.Second
+	cfg.ReadHeaderTimeout = 2 * time.Second
+	cfg.WriteTimeout = 20 * time.Second
+	cfg.IdleTimeout = time.Minute
+	srv = newServer(":9999", http.NotFoundHandler(), cfg)
+	assert.Equal(t, ":9999", srv.Addr)
+	assert.Equal(t, 5*time.Second, srv.ReadTimeout)
+	assert.Equal(t, 2*time.Second, srv.ReadHeaderTimeout)
+	assert.Equal(t, 20*time.Second, srv.WriteTimeout)
+	assert.Equal(t, time.Minute, srv.IdleTimeout)
+}
+
+func TestShutdownServers_ClosesStreamSubscribers(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	hub := handler.NewStreamHub()
+	srv := newServer("", hub, config.Default().Server)
+	srv.RegisterOnShutdown(hub.Close)
+	go srv.Serve(listener)
+
+	resp, err := http.Get("http://" + listener.Addr().String())
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, ": connected\n", line)
+
+	start := time.Now()
+	shutdownServers(5*time.Second, srv)
+
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	assert.Equal(t, 0, hub.Subscribers())
+}
+
+func TestNewAlertsRouter_SearchRequiresAdminAuth(t *testing.T) {
+	tests := []struct {
+		name     string
+		auth     config.AuthConfig
+		token    string
+		expected int
+	}{
+		{"admin auth not configured", config.AuthConfig{}, "", http.StatusNotFound},
+		{"missing credentials", config.AuthConfig{BearerToken: "secret"}, "", http.StatusUnauthorized},
+		{"valid credentials", config.AuthConfig{BearerToken: "secret"}, "secret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.History.Retention = time.Hour
+			cfg.Server.AdminAuth = tt.auth
+			pl, err := newPipeline(cfg)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest("GET", "/alerts/search?label=alertname&value=HighCPU", nil)
+			assert.NoError(t, err)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			rr := httptest.NewRecorder()
+			newAlertsRouter(cfg, pl).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expected, rr.Code)
+		})
+	}
+}
+
+func TestNewAlertsRouter_StreamRequiresAdminAuth(t *testing.T) {
+	tests := []struct {
+		name     string
+		auth     config.AuthConfig
+		expected int
+	}{
+		{"admin auth not configured", config.AuthConfig{}, http.StatusNotFound},
+		{"missing credentials", config.AuthConfig{Username: "admin", Password: "secret"}, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Server.AdminAuth = tt.auth
+			pl, err := newPipeline(cfg)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest("GET", "/alerts/stream", nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			newAlertsRouter(cfg, pl).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expected, rr.Code)
+			assert.Equal(t, 0, pl.streamHub.Subscribers())
+		})
+	}
+}
+
+func TestNewAlertsRouter_CORSOnlyOnBrowserRoutes(t *testing.T) {
+	cfg := config.Default()
+	cfg.Server.CORS.AllowedOrigins = []string{"https://dashboard.example.com"}
+	cfg.Server.AdminAuth = config.AuthConfig{BearerToken: "secret"}
+	pl, err := newPipeline(cfg)
+	assert.NoError(t, err)
+	router := newAlertsRouter(cfg, pl)
+
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		body      string
+		origin    string
+		preflight bool
+		expected  int
+		cors      bool
+	}{
+		{"alerts preflight", "OPTIONS", "/alerts", "", "https://dashboard.example.com", true, http.StatusNoContent, true},
+		{"alerts", "POST", "/alerts", `[{"labels": {"alertname": "CORS"}}]`, "https://dashboard.example.com", false, http.StatusOK, true},
+		{"alerts disallowed origin", "POST", "/alerts", `[]`, "https://evil.example.com", false, http.StatusForbidden, false},
+		{"stream preflight", "OPTIONS", "/alerts/stream", "", "https://dashboard.example.com", true, http.StatusNoContent, true},
+		{"alerts options without preflight", "OPTIONS", "/alerts", "", "https://dashboard.example.com", false, http.StatusMethodNotAllowed, true},
+		{"admin preflight", "OPTIONS", "/admin/pause", "", "https://dashboard.example.com", true, http.StatusMethodNotAllowed, false},
+		{"processors", "GET", "/processors", "", "https://dashboard.example.com", false, http.StatusOK, false},
+		{"health from other origin", "GET", "/health", "", "https://evil.example.com", false, http.StatusOK, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Authorization", "Bearer secret")
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", "POST")
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expected, rr.Code)
+			assert.Equal(t, tt.cors, rr.Header().Get("Access-Control-Allow-Origin") != "")
+		})
+	}
+}
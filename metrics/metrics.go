@@ -3,6 +3,7 @@ package metrics
 import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
 )
 
 var (
@@ -12,12 +13,56 @@ var (
 			Help: "Total number of alerts received by the handler",
 		},
 	)
+
+	AlertsSuppressed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "prometheus_alerts_handler_alerts_suppressed_total",
+			Help: "Total number of alerts dropped before dispatch, by reason",
+		},
+		[]string{"reason"},
+	)
+
+	HeartbeatFailures = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "prometheus_alerts_handler_heartbeat_failures_total",
+			Help: "Total number of failed heartbeat pings",
+		},
+	)
+
+	HTTPRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "prometheus_alerts_handler_http_requests_total",
+			Help: "Total number of HTTP requests served, by route, method and status code",
+		},
+		[]string{"path", "method", "status"},
+	)
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "prometheus_alerts_handler_http_request_duration_seconds",
+			Help:    "Time taken to serve HTTP requests, by route and method",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"path", "method"},
+	)
 )
 
 func init() {
 	prometheus.MustRegister(AlertsReceived)
+	prometheus.MustRegister(AlertsSuppressed)
+	prometheus.MustRegister(HeartbeatFailures)
+	prometheus.MustRegister(HTTPRequests)
+	prometheus.MustRegister(HTTPRequestDuration)
 }
 
-func GetHandler() promhttp.Handler {
-	return promhttp.Handler()
+// GetHandler returns the metrics handler. It serves the OpenMetrics format
+// when the scraper asks for it via the Accept header and the classic
+// Prometheus text format otherwise.
+func GetHandler() http.Handler {
+	return promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{
+			EnableOpenMetrics: true,
+		}),
+	)
 }
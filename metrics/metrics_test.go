@@ -0,0 +1,37 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/igormishsky/prometheus-alerts-handler/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHandler_OpenMetrics(t *testing.T) {
+	req, err := http.NewRequest("GET", "/metrics", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Accept", "application/openmetrics-text; version=0.0.1")
+
+	rr := httptest.NewRecorder()
+	metrics.GetHandler().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Header().Get("Content-Type"), "application/openmetrics-text")
+	assert.True(t, strings.HasSuffix(rr.Body.String(), "# EOF\n"))
+}
+
+func TestGetHandler_TextFormat(t *testing.T) {
+	req, err := http.NewRequest("GET", "/metrics", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	metrics.GetHandler().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Header().Get("Content-Type"), "text/plain")
+	assert.NotContains(t, rr.Body.String(), "# EOF")
+	assert.Contains(t, rr.Body.String(), "prometheus_alerts_handler_alerts_received_total")
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"github.com/igormishsky/prometheus-alerts-handler/config"
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/history"
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/igormishsky/prometheus-alerts-handler/silences"
+)
+
+// pipeline is the configured chain of processors alerts are dispatched
+// through, along with the parts of it that the HTTP routes expose.
+type pipeline struct {
+	processor *processors.PausableProcessor
+	streamHub *handler.StreamHub
+	history   *history.Store
+	silences  *silences.Store
+	enricher  *processors.EnrichProcessor
+	sinks     *processors.Registry
+}
+
+// newPipeline builds the processor chain described by cfg: pause, then
+// enrichment, the resolved guard and silencing, and finally fan-out to the
+// configured sinks, each registered by name so it can be toggled at
+// runtime. It is shared by the server and the replay command.
+func newPipeline(cfg *config.Config) (*pipeline, error) {
+	silenceStore, err := silences.OpenStore(cfg.Silences.Path)
+	if err != nil {
+		return nil, fmt.Errorf("loading silences: %v", err)
+	}
+	pl := &pipeline{
+		streamHub: handler.NewStreamHub(),
+		silences:  silenceStore,
+		sinks:     &processors.Registry{},
+	}
+
+	dispatch := processors.MultiProcessor{&processors.BasicProcessor{}, pl.streamHub}
+	if cfg.EventBridge.EventBusName != "" {
+		client, err := processors.NewEventBridgeClient(cfg.EventBridge.Region)
+		if err != nil {
+			return nil, fmt.Errorf("creating eventbridge client: %v", err)
+		}
+		eventBridgeProcessor, err := processors.NewEventBridgeProcessor(client, cfg.EventBridge.EventBusName, cfg.EventBridge.Source, cfg.EventBridge.DetailType)
+		if err != nil {
+			return nil, fmt.Errorf("creating eventbridge processor: %v", err)
+		}
+		sink, err := pl.addSink("eventbridge", eventBridgeProcessor, cfg.EventBridge.SinkOptions)
+		if err != nil {
+			return nil, err
+		}
+		dispatch = append(dispatch, sink)
+	}
+	if cfg.AMQP.URL != "" {
+		dial, err := processors.DialAMQP(cfg.AMQP.URL, cfg.AMQP.Exchange, cfg.AMQP.ExchangeType)
+		if err != nil {
+			return nil, fmt.Errorf("configuring amqp connection: %v", err)
+		}
+		amqpProcessor, err := processors.NewAMQPProcessor(dial, cfg.AMQP.Exchange, cfg.AMQP.RoutingKey)
+		if err != nil {
+			return nil, fmt.Errorf("creating amqp processor: %v", err)
+		}
+		sink, err := pl.addSink("amqp", amqpProcessor, cfg.AMQP.SinkOptions)
+		if err != nil {
+			return nil, err
+		}
+		dispatch = append(dispatch, sink)
+	}
+	if cfg.Stdout.Enabled {
+		stdoutProcessor, err := processors.NewStdoutProcessor(cfg.Stdout.Format)
+		if err != nil {
+			return nil, fmt.Errorf("creating stdout processor: %v", err)
+		}
+		sink, err := pl.addSink("stdout", stdoutProcessor, cfg.Stdout.SinkOptions)
+		if err != nil {
+			return nil, err
+		}
+		dispatch = append(dispatch, sink)
+	}
+	if cfg.AdaptiveCard.WebhookURL != "" {
+		adaptiveCardProcessor, err := processors.NewAdaptiveCardProcessor(cfg.AdaptiveCard.WebhookURL, cfg.AdaptiveCard.CardTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("creating adaptive card processor: %v", err)
+		}
+		sink, err := pl.addSink("adaptivecard", adaptiveCardProcessor, cfg.AdaptiveCard.SinkOptions)
+		if err != nil {
+			return nil, err
+		}
+		dispatch = append(dispatch, sink)
+	}
+	if cfg.PowerAutomate.FlowURL != "" {
+		powerAutomateProcessor, err := processors.NewPowerAutomateProcessor(cfg.PowerAutomate.FlowURL)
+		if err != nil {
+			return nil, fmt.Errorf("creating power automate processor: %v", err)
+		}
+		sink, err := pl.addSink("powerautomate", powerAutomateProcessor, cfg.PowerAutomate.SinkOptions)
+		if err != nil {
+			return nil, err
+		}
+		dispatch = append(dispatch, sink)
+	}
+	if cfg.History.Retention > 0 {
+		pl.history = history.New(cfg.History.Retention, cfg.History.MaxEntries)
+		dispatch = append(dispatch, pl.history)
+	}
+
+	silencer := &processors.SilencingProcessor{Next: dispatch, Silences: pl.silences}
+	var guarded processors.AlertProcessor = silencer
+	if cfg.Alerts.RequireFiringBeforeResolved {
+		guarded = processors.NewResolvedGuardProcessor(cfg.Alerts.ResolvedGracePeriod, cfg.Alerts.ResolvedStateTTL, silencer)
+	}
+	pl.processor = &processors.PausableProcessor{Next: guarded}
+	if cfg.Enrichment.MappingFile != "" {
+		enricher, err := processors.NewEnrichProcessor(cfg.Enrichment.MappingFile, guarded)
+		if err != nil {
+			return nil, fmt.Errorf("loading enrichment mapping: %v", err)
+		}
+		pl.enricher = enricher
+		pl.processor.Next = enricher
+	}
+	return pl, nil
+}
+
+// addSink wraps p in the redaction and severity threshold configured for
+// it and registers the result under name. Redaction is innermost so only p
+// sees stripped alerts, and so alerts injected by name are stripped too.
+func (pl *pipeline) addSink(name string, p processors.AlertProcessor, opts config.SinkOptions) (processors.AlertProcessor, error) {
+	if len(opts.RedactLabels) > 0 || len(opts.RedactAnnotations) > 0 {
+		p = processors.NewRedactingProcessor(opts.RedactLabels, opts.RedactAnnotations, p)
+	}
+	next := p
+	if opts.MinSeverity != "" {
+		sp, err := processors.NewSeverityProcessor(opts.MinSeverity, p)
+		if err != nil {
+			return nil, err
+		}
+		next = sp
+	}
+	return pl.sinks.Register(name, next, p), nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/igormishsky/prometheus-alerts-handler/config"
+	"github.com/igormishsky/prometheus-alerts-handler/replay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPipeline_ReplayReachesConfiguredSinks(t *testing.T) {
+	var received int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Contains(t, string(body), `"alertname":"Replayed"`)
+		atomic.AddInt32(&received, 1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	cfg := config.Default()
+	cfg.PowerAutomate.FlowURL = server.URL
+	cfg.PowerAutomate.MinSeverity = "warning"
+
+	pl, err := newPipeline(cfg)
+	assert.NoError(t, err)
+
+	input := `{"status": "firing", "labels": {"alertname": "Replayed", "severity": "critical"}}
+{"status": "firing", "labels": {"alertname": "Replayed", "severity": "info"}}
+`
+	count, err := replay.Replay(strings.NewReader(input), pl.processor, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&received))
+}
+
+func TestNewPipeline_InvalidSinkOptions(t *testing.T) {
+	cfg := config.Default()
+	cfg.Stdout.Enabled = true
+	cfg.Stdout.MinSeverity = "urgent"
+
+	_, err := newPipeline(cfg)
+	assert.Error(t, err)
+}
+
+func TestNewPipeline_RegistersSinksByName(t *testing.T) {
+	cfg := config.Default()
+	cfg.Stdout.Enabled = true
+	cfg.PowerAutomate.FlowURL = "http://127.0.0.1:0/flow"
+
+	pl, err := newPipeline(cfg)
+	assert.NoError(t, err)
+
+	var names []string
+	for _, tp := range pl.sinks.Sinks() {
+		names = append(names, tp.Name)
+	}
+	assert.Equal(t, []string{"stdout", "powerautomate"}, names)
+}
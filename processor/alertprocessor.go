@@ -2,11 +2,12 @@ package processor
 
 import (
 	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/redact"
 	"github.com/sirupsen/logrus"
 )
 
 func ProcessAlert(alert handler.Alert) {
-	logrus.Info("Processing alert:", alert)
+	logrus.Info("Processing alert:", redact.Alert(alert))
 
 	severity, ok := alert.Labels["severity"]
 	if !ok {
@@ -25,11 +26,11 @@ func ProcessAlert(alert handler.Alert) {
 }
 
 func processCriticalAlert(alert handler.Alert) {
-	logrus.Error("Critical alert:", alert)
+	logrus.Error("Critical alert:", redact.Alert(alert))
 	// Implement critical alert handling logic
 }
 
 func processWarningAlert(alert handler.Alert) {
-	logrus.Warn("Warning alert:", alert)
+	logrus.Warn("Warning alert:", redact.Alert(alert))
 	// Implement warning alert handling logic
 }
@@ -0,0 +1,148 @@
+package processors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	AdaptiveCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	AdaptiveCardVersion     = "1.4"
+	AdaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+)
+
+// AdaptiveCardProcessor posts each alert as a Microsoft Adaptive Card, the
+// format accepted by Teams and other chat tools' incoming webhooks.
+type AdaptiveCardProcessor struct {
+	webhookURL string
+	template   *template.Template
+	client     *http.Client
+}
+
+// cardFuncs are available to card templates. duration renders how long the
+// alert has been firing, e.g. {{duration .}}. json renders a value as a JSON
+// literal, quotes included, and should wrap every label or annotation value
+// so quotes and newlines in it cannot break the card:
+// "text": {{json .Annotations.description}}.
+var cardFuncs = template.FuncMap{
+	"duration": alertDuration,
+	"json":     toJSON,
+}
+
+func toJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// NewAdaptiveCardProcessor returns a processor posting to webhookURL. When
+// cardTemplate is set it is a text/template, executed against the alert,
+// that renders the card JSON; otherwise a default card is built. The
+// template does no escaping of its own, so values should go through json.
+func NewAdaptiveCardProcessor(webhookURL, cardTemplate string) (*AdaptiveCardProcessor, error) {
+	if webhookURL == "" {
+		return nil, fmt.Errorf("adaptive card processor requires a webhook URL")
+	}
+
+	ap := &AdaptiveCardProcessor{
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+	if cardTemplate != "" {
+		tmpl, err := template.New("card").Funcs(cardFuncs).Parse(cardTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid card template: %v", err)
+		}
+		ap.template = tmpl
+	}
+	return ap, nil
+}
+
+func (ap *AdaptiveCardProcessor) Process(alert types.Alert) {
+	card, err := ap.buildCard(alert)
+	if err != nil {
+		logrus.Error("Error building adaptive card:", err)
+		return
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{
+				"contentType": AdaptiveCardContentType,
+				"content":     card,
+			},
+		},
+	})
+	if err != nil {
+		logrus.Error("Error marshalling adaptive card:", err)
+		return
+	}
+
+	resp, err := ap.client.Post(ap.webhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		logrus.Error("Error sending adaptive card:", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		logrus.Error("Adaptive card webhook returned status ", resp.StatusCode)
+	}
+}
+
+func (ap *AdaptiveCardProcessor) buildCard(alert types.Alert) (json.RawMessage, error) {
+	if ap.template == nil {
+		return json.Marshal(defaultCard(alert))
+	}
+
+	var b bytes.Buffer
+	if err := ap.template.Execute(&b, alert); err != nil {
+		return nil, err
+	}
+	if !json.Valid(b.Bytes()) {
+		return nil, fmt.Errorf("card template did not render valid JSON")
+	}
+	return b.Bytes(), nil
+}
+
+func defaultCard(alert types.Alert) map[string]interface{} {
+	body := []map[string]interface{}{
+		{
+			"type":   "TextBlock",
+			"text":   fmt.Sprintf("[%s] %s", strings.ToUpper(alert.Status), alert.Labels["alertname"]),
+			"size":   "Medium",
+			"weight": "Bolder",
+			"wrap":   true,
+		},
+	}
+
+	if text := alertDuration(alert); text != "" {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": text, "isSubtle": true})
+	}
+	if text := alert.Annotations["summary"]; text != "" {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": text, "wrap": true})
+	}
+	if text := alert.Annotations["description"]; text != "" {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": text, "wrap": true})
+	}
+
+	facts := make([]map[string]string, 0, len(alert.Labels))
+	for _, name := range types.SortedKeys(alert.Labels) {
+		facts = append(facts, map[string]string{"title": name, "value": alert.Labels[name]})
+	}
+	body = append(body, map[string]interface{}{"type": "FactSet", "facts": facts})
+
+	return map[string]interface{}{
+		"$schema": AdaptiveCardSchema,
+		"type":    "AdaptiveCard",
+		"version": AdaptiveCardVersion,
+		"body":    body,
+	}
+}
@@ -0,0 +1,121 @@
+package processors_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/stretchr/testify/assert"
+)
+
+type adaptiveCardMessage struct {
+	Type        string `json:"type"`
+	Attachments []struct {
+		ContentType string                 `json:"contentType"`
+		Content     map[string]interface{} `json:"content"`
+	} `json:"attachments"`
+}
+
+func receiveCard(t *testing.T, cardTemplate string, alert handler.Alert) adaptiveCardMessage {
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		received, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ap, err := processors.NewAdaptiveCardProcessor(server.URL, cardTemplate)
+	assert.NoError(t, err)
+	ap.Process(alert)
+
+	var msg adaptiveCardMessage
+	assert.NoError(t, json.Unmarshal(received, &msg))
+	assert.Equal(t, "message", msg.Type)
+	assert.Len(t, msg.Attachments, 1)
+	assert.Equal(t, processors.AdaptiveCardContentType, msg.Attachments[0].ContentType)
+	return msg
+}
+
+func TestAdaptiveCardProcessor_DefaultCard(t *testing.T) {
+	msg := receiveCard(t, "", handler.Alert{
+		Status: "firing",
+		Labels: map[string]string{
+			"alertname": "DiskFull",
+			"severity":  "critical",
+		},
+		Annotations: map[string]string{
+			"summary": "Disk is full",
+		},
+	})
+
+	card := msg.Attachments[0].Content
+	assert.Equal(t, processors.AdaptiveCardSchema, card["$schema"])
+	assert.Equal(t, "AdaptiveCard", card["type"])
+	assert.Equal(t, "1.4", card["version"])
+
+	body := card["body"].([]interface{})
+	assert.Len(t, body, 3)
+	assert.Equal(t, "[FIRING] DiskFull", body[0].(map[string]interface{})["text"])
+	assert.Equal(t, "Disk is full", body[1].(map[string]interface{})["text"])
+
+	facts := body[2].(map[string]interface{})["facts"].([]interface{})
+	assert.Equal(t, map[string]interface{}{"title": "alertname", "value": "DiskFull"}, facts[0])
+	assert.Equal(t, map[string]interface{}{"title": "severity", "value": "critical"}, facts[1])
+}
+
+func TestAdaptiveCardProcessor_CardTemplate(t *testing.T) {
+	cardTemplate := `{"type": "AdaptiveCard", "version": "1.4", "body": [{"type": "TextBlock", "text": "{{.Labels.alertname}} is {{.Status}}"}]}`
+
+	msg := receiveCard(t, cardTemplate, handler.Alert{
+		Status: "resolved",
+		Labels: map[string]string{"alertname": "DiskFull"},
+	})
+
+	body := msg.Attachments[0].Content["body"].([]interface{})
+	assert.Equal(t, "DiskFull is resolved", body[0].(map[string]interface{})["text"])
+}
+
+func TestNewAdaptiveCardProcessor_Validation(t *testing.T) {
+	_, err := processors.NewAdaptiveCardProcessor("", "")
+	assert.Error(t, err)
+
+	_, err = processors.NewAdaptiveCardProcessor("http://example.com", "{{.Labels")
+	assert.Error(t, err)
+}
+
+func TestAdaptiveCardProcessor_Duration(t *testing.T) {
+	alert := handler.Alert{
+		Status:   "firing",
+		Labels:   map[string]string{"alertname": "DiskFull"},
+		StartsAt: time.Now().Add(-90*time.Minute - 10*time.Second).Format(time.RFC3339),
+	}
+
+	msg := receiveCard(t, "", alert)
+	body := msg.Attachments[0].Content["body"].([]interface{})
+	assert.Equal(t, "Firing for 1h 30m", body[1].(map[string]interface{})["text"])
+
+	cardTemplate := `{"type": "AdaptiveCard", "version": "1.4", "body": [{"type": "TextBlock", "text": "{{duration .}}"}]}`
+	msg = receiveCard(t, cardTemplate, alert)
+	body = msg.Attachments[0].Content["body"].([]interface{})
+	assert.Equal(t, "Firing for 1h 30m", body[0].(map[string]interface{})["text"])
+}
+
+func TestAdaptiveCardProcessor_CardTemplateEscaping(t *testing.T) {
+	cardTemplate := `{"type": "AdaptiveCard", "version": "1.4", "body": [{"type": "TextBlock", "text": {{json .Annotations.description}}}]}`
+	description := "Disk \"data\" is full\non db-1\t(95%)"
+
+	msg := receiveCard(t, cardTemplate, handler.Alert{
+		Status:      "firing",
+		Labels:      map[string]string{"alertname": "DiskFull"},
+		Annotations: map[string]string{"description": description},
+	})
+
+	body := msg.Attachments[0].Content["body"].([]interface{})
+	assert.Equal(t, description, body[0].(map[string]interface{})["text"])
+}
@@ -0,0 +1,134 @@
+package processors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// AMQPChannel is the part of an AMQP channel the processor publishes with,
+// so tests can substitute it. Close releases the underlying connection.
+type AMQPChannel interface {
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	Close() error
+}
+
+// AMQPDialFunc opens a channel to publish on.
+type AMQPDialFunc func() (AMQPChannel, error)
+
+// AMQPProcessor publishes each alert as a persistent JSON message to an
+// AMQP exchange. The connection is opened on the first alert and reopened
+// after a failed publish, so a broker restart costs at most one alert.
+type AMQPProcessor struct {
+	dial       AMQPDialFunc
+	exchange   string
+	routingKey string
+	timeout    time.Duration
+
+	mu      sync.Mutex
+	channel AMQPChannel
+}
+
+func NewAMQPProcessor(dial AMQPDialFunc, exchange, routingKey string) (*AMQPProcessor, error) {
+	if dial == nil {
+		return nil, fmt.Errorf("amqp processor requires a dialer")
+	}
+	if exchange == "" && routingKey == "" {
+		return nil, fmt.Errorf("amqp processor requires an exchange or a routing key")
+	}
+	return &AMQPProcessor{
+		dial:       dial,
+		exchange:   exchange,
+		routingKey: routingKey,
+		timeout:    10 * time.Second,
+	}, nil
+}
+
+func (ap *AMQPProcessor) Process(alert types.Alert) {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		logrus.Error("Error marshalling amqp message:", err)
+		return
+	}
+
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+
+	if ap.channel == nil {
+		ch, err := ap.dial()
+		if err != nil {
+			logrus.Error("Error connecting to amqp broker:", err)
+			return
+		}
+		ap.channel = ch
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ap.timeout)
+	defer cancel()
+
+	err = ap.channel.PublishWithContext(ctx, ap.exchange, ap.routingKey, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		Body:         body,
+	})
+	if err != nil {
+		logrus.Error("Error publishing to amqp exchange:", err)
+		ap.channel.Close()
+		ap.channel = nil
+	}
+}
+
+var amqpExchangeTypes = map[string]bool{
+	amqp.ExchangeDirect:  true,
+	amqp.ExchangeFanout:  true,
+	amqp.ExchangeTopic:   true,
+	amqp.ExchangeHeaders: true,
+}
+
+// DialAMQP returns an AMQPDialFunc connecting to the broker at url. When
+// exchangeType is set, exchange is declared as a durable exchange of that
+// type on every connect; otherwise it must already exist.
+func DialAMQP(url, exchange, exchangeType string) (AMQPDialFunc, error) {
+	if _, err := amqp.ParseURI(url); err != nil {
+		return nil, fmt.Errorf("invalid amqp url: %v", err)
+	}
+	if exchangeType != "" && !amqpExchangeTypes[exchangeType] {
+		return nil, fmt.Errorf("unknown amqp exchange type %q", exchangeType)
+	}
+
+	return func() (AMQPChannel, error) {
+		conn, err := amqp.Dial(url)
+		if err != nil {
+			return nil, err
+		}
+		ch, err := conn.Channel()
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if exchangeType != "" {
+			if err := ch.ExchangeDeclare(exchange, exchangeType, true, false, false, false, nil); err != nil {
+				conn.Close()
+				return nil, err
+			}
+		}
+		return amqpConnChannel{Channel: ch, conn: conn}, nil
+	}, nil
+}
+
+// amqpConnChannel closes the connection along with the channel, since each
+// dial opens a connection of its own.
+type amqpConnChannel struct {
+	*amqp.Channel
+	conn *amqp.Connection
+}
+
+func (c amqpConnChannel) Close() error {
+	return c.conn.Close()
+}
@@ -1,18 +1,19 @@
 package processors
 
 import (
-	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/redact"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
 	"github.com/sirupsen/logrus"
 )
 
 type AlertProcessor interface {
-	Process(alert handler.Alert)
+	Process(alert types.Alert)
 }
 
 type BasicProcessor struct{}
 
-func (bp *BasicProcessor) Process(alert handler.Alert) {
-	logrus.Info("Processing alert:", alert)
+func (bp *BasicProcessor) Process(alert types.Alert) {
+	logrus.Info("Processing alert:", redact.Alert(alert))
 
 	severity, ok := alert.Labels["severity"]
 	if !ok {
@@ -30,12 +31,12 @@ func (bp *BasicProcessor) Process(alert handler.Alert) {
 	}
 }
 
-func (bp *BasicProcessor) processCriticalAlert(alert handler.Alert) {
-	logrus.Error("Critical alert:", alert)
+func (bp *BasicProcessor) processCriticalAlert(alert types.Alert) {
+	logrus.Error("Critical alert:", redact.Alert(alert))
 	// Implement critical alert handling logic
 }
 
-func (bp *BasicProcessor) processWarningAlert(alert handler.Alert) {
-	logrus.Warn("Warning alert:", alert)
+func (bp *BasicProcessor) processWarningAlert(alert types.Alert) {
+	logrus.Warn("Warning alert:", redact.Alert(alert))
 	// Implement warning alert handling logic
 }
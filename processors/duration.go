@@ -0,0 +1,41 @@
+package processors
+
+import (
+	"fmt"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"time"
+)
+
+// HumanizeDuration renders d using its two most significant units, e.g.
+// "45s", "5m 3s", "1h 30m" or "2d 4h".
+func HumanizeDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	seconds := int(d % time.Minute / time.Second)
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm %ds", minutes, seconds)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
+
+// alertDuration describes how long alert has been firing, or fired for once
+// resolved. It returns "" when the alert has no usable StartsAt.
+func alertDuration(alert types.Alert) string {
+	d, ok := alert.Duration(time.Now())
+	if !ok {
+		return ""
+	}
+	if alert.Status == "resolved" {
+		return "Resolved after " + HumanizeDuration(d)
+	}
+	return "Firing for " + HumanizeDuration(d)
+}
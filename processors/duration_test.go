@@ -0,0 +1,16 @@
+package processors_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHumanizeDuration(t *testing.T) {
+	assert.Equal(t, "45s", processors.HumanizeDuration(45*time.Second))
+	assert.Equal(t, "5m 3s", processors.HumanizeDuration(5*time.Minute+3*time.Second))
+	assert.Equal(t, "1h 30m", processors.HumanizeDuration(90*time.Minute+10*time.Second))
+	assert.Equal(t, "2d 4h", processors.HumanizeDuration(52*time.Hour))
+}
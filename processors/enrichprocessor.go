@@ -0,0 +1,74 @@
+package processors
+
+import (
+	"fmt"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"sync"
+)
+
+// enrichMapping is the format of the enrichment mapping file: alerts whose
+// MatchLabel value has an entry in Values get that entry's labels and
+// annotations.
+type enrichMapping struct {
+	MatchLabel string                     `yaml:"match_label"`
+	Values     map[string]enrichAdditions `yaml:"values"`
+}
+
+type enrichAdditions struct {
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// EnrichProcessor adds static labels and annotations from a mapping file to
+// each alert before forwarding it to Next. Keys the alert already sets are
+// left untouched.
+type EnrichProcessor struct {
+	Next AlertProcessor
+
+	path    string
+	mu      sync.RWMutex
+	mapping enrichMapping
+}
+
+func NewEnrichProcessor(path string, next AlertProcessor) (*EnrichProcessor, error) {
+	ep := &EnrichProcessor{Next: next, path: path}
+	if err := ep.Reload(); err != nil {
+		return nil, err
+	}
+	return ep, nil
+}
+
+// Reload re-reads the mapping file. On error the previous mapping is kept.
+func (ep *EnrichProcessor) Reload() error {
+	data, err := ioutil.ReadFile(ep.path)
+	if err != nil {
+		return err
+	}
+
+	var mapping enrichMapping
+	if err := yaml.Unmarshal(data, &mapping); err != nil {
+		return err
+	}
+	if mapping.MatchLabel == "" {
+		return fmt.Errorf("%s: match_label is required", ep.path)
+	}
+
+	ep.mu.Lock()
+	ep.mapping = mapping
+	ep.mu.Unlock()
+	return nil
+}
+
+func (ep *EnrichProcessor) Process(alert types.Alert) {
+	ep.mu.RLock()
+	additions, ok := ep.mapping.Values[alert.Labels[ep.mapping.MatchLabel]]
+	ep.mu.RUnlock()
+
+	if ok {
+		alert.Labels = types.MergeMissing(alert.Labels, additions.Labels)
+		alert.Annotations = types.MergeMissing(alert.Annotations, additions.Annotations)
+	}
+	ep.Next.Process(alert)
+}
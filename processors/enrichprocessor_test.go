@@ -0,0 +1,77 @@
+package processors_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingProcessor struct {
+	alerts []handler.Alert
+}
+
+func (rp *recordingProcessor) Process(alert handler.Alert) {
+	rp.alerts = append(rp.alerts, alert)
+}
+
+const enrichMapping = `
+match_label: service
+values:
+  checkout:
+    labels:
+      team: payments
+    annotations:
+      owner: payments-oncall
+      runbook_url: https://runbooks.example.com/checkout
+`
+
+func TestEnrichProcessor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mapping.yaml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(enrichMapping), 0600))
+
+	next := &recordingProcessor{}
+	ep, err := processors.NewEnrichProcessor(path, next)
+	assert.NoError(t, err)
+
+	ep.Process(handler.Alert{
+		Status:      "firing",
+		Labels:      map[string]string{"alertname": "HighLatency", "service": "checkout"},
+		Annotations: map[string]string{"runbook_url": "https://runbooks.example.com/latency"},
+	})
+	ep.Process(handler.Alert{
+		Status: "firing",
+		Labels: map[string]string{"alertname": "HighLatency", "service": "search"},
+	})
+
+	assert.Len(t, next.alerts, 2)
+
+	enriched := next.alerts[0]
+	assert.Equal(t, "payments", enriched.Labels["team"])
+	assert.Equal(t, "payments-oncall", enriched.Annotations["owner"])
+	assert.Equal(t, "https://runbooks.example.com/latency", enriched.Annotations["runbook_url"])
+
+	assert.NotContains(t, next.alerts[1].Labels, "team")
+}
+
+func TestEnrichProcessor_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mapping.yaml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(enrichMapping), 0600))
+
+	next := &recordingProcessor{}
+	ep, err := processors.NewEnrichProcessor(path, next)
+	assert.NoError(t, err)
+
+	updated := "match_label: service\nvalues:\n  checkout:\n    labels:\n      team: storefront\n"
+	assert.NoError(t, ioutil.WriteFile(path, []byte(updated), 0600))
+	assert.NoError(t, ep.Reload())
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("values: ["), 0600))
+	assert.Error(t, ep.Reload())
+
+	ep.Process(handler.Alert{Labels: map[string]string{"service": "checkout"}})
+	assert.Equal(t, "storefront", next.alerts[0].Labels["team"])
+}
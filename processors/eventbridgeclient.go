@@ -0,0 +1,58 @@
+//go:build eventbridge
+// +build eventbridge
+
+package processors
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	ebtypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+)
+
+// NewEventBridgeClient returns an EventBridge client for region, or the
+// region from the environment when empty. Credentials come from the AWS
+// SDK's default chain: environment, shared config and SSO, web identity,
+// and the EC2/ECS instance role.
+func NewEventBridgeClient(region string) (EventBridgeAPI, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS config: %v", err)
+	}
+	return sdkEventBridge{client: eventbridge.NewFromConfig(cfg)}, nil
+}
+
+type sdkEventBridge struct {
+	client *eventbridge.Client
+}
+
+func (s sdkEventBridge) PutEvents(ctx context.Context, entries []EventBridgeEntry) error {
+	input := &eventbridge.PutEventsInput{}
+	for _, e := range entries {
+		input.Entries = append(input.Entries, ebtypes.PutEventsRequestEntry{
+			EventBusName: aws.String(e.EventBusName),
+			Source:       aws.String(e.Source),
+			DetailType:   aws.String(e.DetailType),
+			Detail:       aws.String(e.Detail),
+		})
+	}
+
+	out, err := s.client.PutEvents(ctx, input)
+	if err != nil {
+		return err
+	}
+	// PutEvents succeeds as a call even when individual entries are
+	// rejected, so check each one.
+	for _, e := range out.Entries {
+		if e.ErrorCode != nil {
+			return fmt.Errorf("event rejected: %s: %s", aws.ToString(e.ErrorCode), aws.ToString(e.ErrorMessage))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build !eventbridge
+// +build !eventbridge
+
+package processors
+
+import (
+	"fmt"
+)
+
+// NewEventBridgeClient fails in builds without the eventbridge tag, which
+// keep the AWS SDK out of the default binary.
+func NewEventBridgeClient(region string) (EventBridgeAPI, error) {
+	return nil, fmt.Errorf("eventbridge support is not built in; rebuild with -tags eventbridge")
+}
@@ -0,0 +1,75 @@
+package processors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// EventBridgeEntry is a single event put onto an EventBridge bus.
+type EventBridgeEntry struct {
+	EventBusName string
+	Source       string
+	DetailType   string
+	Detail       string
+}
+
+// EventBridgeAPI wraps the EventBridge PutEvents call so the processor can
+// be tested without AWS. NewEventBridgeClient returns the real client.
+type EventBridgeAPI interface {
+	PutEvents(ctx context.Context, entries []EventBridgeEntry) error
+}
+
+// EventBridgeProcessor puts each alert onto an Amazon EventBridge bus, with
+// the alert's JSON as the event detail.
+type EventBridgeProcessor struct {
+	client       EventBridgeAPI
+	eventBusName string
+	source       string
+	detailType   string
+	timeout      time.Duration
+}
+
+func NewEventBridgeProcessor(client EventBridgeAPI, eventBusName, source, detailType string) (*EventBridgeProcessor, error) {
+	switch {
+	case client == nil:
+		return nil, fmt.Errorf("eventbridge processor requires a client")
+	case eventBusName == "":
+		return nil, fmt.Errorf("eventbridge processor requires an event bus name")
+	case source == "":
+		return nil, fmt.Errorf("eventbridge processor requires a source")
+	case detailType == "":
+		return nil, fmt.Errorf("eventbridge processor requires a detail type")
+	}
+	return &EventBridgeProcessor{
+		client:       client,
+		eventBusName: eventBusName,
+		source:       source,
+		detailType:   detailType,
+		timeout:      10 * time.Second,
+	}, nil
+}
+
+func (ep *EventBridgeProcessor) Process(alert types.Alert) {
+	detail, err := json.Marshal(alert)
+	if err != nil {
+		logrus.Error("Error marshalling eventbridge detail:", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ep.timeout)
+	defer cancel()
+
+	err = ep.client.PutEvents(ctx, []EventBridgeEntry{{
+		EventBusName: ep.eventBusName,
+		Source:       ep.source,
+		DetailType:   ep.detailType,
+		Detail:       string(detail),
+	}})
+	if err != nil {
+		logrus.Error("Error putting event on eventbridge:", err)
+	}
+}
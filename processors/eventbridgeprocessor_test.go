@@ -0,0 +1,78 @@
+package processors_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEventBridge struct {
+	entries []processors.EventBridgeEntry
+	err     error
+}
+
+func (f *fakeEventBridge) PutEvents(ctx context.Context, entries []processors.EventBridgeEntry) error {
+	f.entries = append(f.entries, entries...)
+	return f.err
+}
+
+func TestEventBridgeProcessor(t *testing.T) {
+	client := &fakeEventBridge{}
+	ep, err := processors.NewEventBridgeProcessor(client, "alerts", "prometheus.alerts", "Prometheus Alert")
+	assert.NoError(t, err)
+
+	alert := handler.Alert{
+		Status:      "firing",
+		Labels:      map[string]string{"alertname": "DiskFull", "severity": "critical"},
+		Annotations: map[string]string{"summary": "Disk is full"},
+		StartsAt:    "2024-01-01T00:00:00Z",
+	}
+	ep.Process(alert)
+
+	assert.Len(t, client.entries, 1)
+	entry := client.entries[0]
+	assert.Equal(t, "alerts", entry.EventBusName)
+	assert.Equal(t, "prometheus.alerts", entry.Source)
+	assert.Equal(t, "Prometheus Alert", entry.DetailType)
+
+	var detail handler.Alert
+	assert.NoError(t, json.Unmarshal([]byte(entry.Detail), &detail))
+	assert.Equal(t, alert, detail)
+}
+
+func TestEventBridgeProcessor_PutEventsError(t *testing.T) {
+	client := &fakeEventBridge{err: errors.New("throttled")}
+	ep, err := processors.NewEventBridgeProcessor(client, "alerts", "prometheus.alerts", "Prometheus Alert")
+	assert.NoError(t, err)
+
+	ep.Process(handler.Alert{Labels: map[string]string{"alertname": "DiskFull"}})
+	assert.Len(t, client.entries, 1)
+}
+
+func TestNewEventBridgeProcessor_RequiredFields(t *testing.T) {
+	client := &fakeEventBridge{}
+	tests := []struct {
+		name       string
+		client     processors.EventBridgeAPI
+		bus        string
+		source     string
+		detailType string
+	}{
+		{"missing client", nil, "alerts", "prometheus.alerts", "Prometheus Alert"},
+		{"missing event bus name", client, "", "prometheus.alerts", "Prometheus Alert"},
+		{"missing source", client, "alerts", "", "Prometheus Alert"},
+		{"missing detail type", client, "alerts", "prometheus.alerts", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := processors.NewEventBridgeProcessor(tt.client, tt.bus, tt.source, tt.detailType)
+			assert.Error(t, err)
+		})
+	}
+}
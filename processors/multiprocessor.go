@@ -0,0 +1,14 @@
+package processors
+
+import (
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+)
+
+// MultiProcessor dispatches each alert to every processor in order.
+type MultiProcessor []AlertProcessor
+
+func (mp MultiProcessor) Process(alert types.Alert) {
+	for _, p := range mp {
+		p.Process(alert)
+	}
+}
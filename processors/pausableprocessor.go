@@ -0,0 +1,37 @@
+package processors
+
+import (
+	"github.com/igormishsky/prometheus-alerts-handler/metrics"
+	"github.com/igormishsky/prometheus-alerts-handler/redact"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"github.com/sirupsen/logrus"
+	"sync/atomic"
+)
+
+// PausableProcessor forwards alerts to Next unless dispatch is paused, in
+// which case alerts are dropped and counted as suppressed.
+type PausableProcessor struct {
+	Next   AlertProcessor
+	paused int32
+}
+
+func (pp *PausableProcessor) Process(alert types.Alert) {
+	if pp.Paused() {
+		logrus.Warn("Dispatch paused, dropping alert:", redact.Alert(alert))
+		metrics.AlertsSuppressed.WithLabelValues("paused").Inc()
+		return
+	}
+	pp.Next.Process(alert)
+}
+
+func (pp *PausableProcessor) Pause() {
+	atomic.StoreInt32(&pp.paused, 1)
+}
+
+func (pp *PausableProcessor) Resume() {
+	atomic.StoreInt32(&pp.paused, 0)
+}
+
+func (pp *PausableProcessor) Paused() bool {
+	return atomic.LoadInt32(&pp.paused) == 1
+}
@@ -0,0 +1,84 @@
+package processors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// PowerAutomatePayload is the flat body posted to a Flow HTTP trigger. Every
+// field is always present so the trigger's request schema can be fixed.
+// Labels are rendered as sorted "name=value" pairs joined by ", ".
+type PowerAutomatePayload struct {
+	AlertName   string `json:"alertname"`
+	Status      string `json:"status"`
+	Severity    string `json:"severity"`
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+	StartsAt    string `json:"starts_at"`
+	EndsAt      string `json:"ends_at"`
+	Fingerprint string `json:"fingerprint"`
+	Labels      string `json:"labels"`
+}
+
+// PowerAutomateProcessor posts each alert to a Microsoft Power Automate flow
+// started by an HTTP request trigger.
+type PowerAutomateProcessor struct {
+	flowURL string
+	client  *http.Client
+}
+
+func NewPowerAutomateProcessor(flowURL string) (*PowerAutomateProcessor, error) {
+	if flowURL == "" {
+		return nil, fmt.Errorf("power automate processor requires a flow URL")
+	}
+	return &PowerAutomateProcessor{
+		flowURL: flowURL,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (pp *PowerAutomateProcessor) Process(alert types.Alert) {
+	payload, err := json.Marshal(flowPayload(alert))
+	if err != nil {
+		logrus.Error("Error marshalling power automate payload:", err)
+		return
+	}
+
+	resp, err := pp.client.Post(pp.flowURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		logrus.Error("Error triggering power automate flow:", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	// Flow triggers answer 202 Accepted unless the flow sends its own
+	// response, in which case any 2xx is a success.
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		logrus.Error("Power automate flow returned status ", resp.StatusCode)
+	}
+}
+
+func flowPayload(alert types.Alert) PowerAutomatePayload {
+	pairs := make([]string, 0, len(alert.Labels))
+	for _, name := range types.SortedKeys(alert.Labels) {
+		pairs = append(pairs, name+"="+alert.Labels[name])
+	}
+
+	return PowerAutomatePayload{
+		AlertName:   alert.Labels["alertname"],
+		Status:      alert.Status,
+		Severity:    alert.Labels["severity"],
+		Summary:     alert.Annotations["summary"],
+		Description: alert.Annotations["description"],
+		StartsAt:    alert.StartsAt,
+		EndsAt:      alert.EndsAt,
+		Fingerprint: alert.Fingerprint(),
+		Labels:      strings.Join(pairs, ", "),
+	}
+}
@@ -0,0 +1,59 @@
+package processors_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPowerAutomateProcessor(t *testing.T) {
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		received, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	pp, err := processors.NewPowerAutomateProcessor(server.URL)
+	assert.NoError(t, err)
+
+	alert := handler.Alert{
+		Status:      "firing",
+		Labels:      map[string]string{"alertname": "DiskFull", "severity": "critical", "instance": "db-1"},
+		Annotations: map[string]string{"summary": "Disk is full"},
+		StartsAt:    "2024-01-01T00:00:00Z",
+	}
+	pp.Process(alert)
+
+	var flat map[string]interface{}
+	assert.NoError(t, json.Unmarshal(received, &flat))
+	for _, v := range flat {
+		assert.IsType(t, "", v, "payload must be flat")
+	}
+
+	var payload processors.PowerAutomatePayload
+	assert.NoError(t, json.Unmarshal(received, &payload))
+	assert.Equal(t, processors.PowerAutomatePayload{
+		AlertName:   "DiskFull",
+		Status:      "firing",
+		Severity:    "critical",
+		Summary:     "Disk is full",
+		StartsAt:    "2024-01-01T00:00:00Z",
+		Fingerprint: alert.Fingerprint(),
+		Labels:      "alertname=DiskFull, instance=db-1, severity=critical",
+	}, payload)
+	assert.Contains(t, flat, "ends_at")
+	assert.Contains(t, flat, "description")
+}
+
+func TestNewPowerAutomateProcessor_RequiresURL(t *testing.T) {
+	_, err := processors.NewPowerAutomateProcessor("")
+	assert.Error(t, err)
+}
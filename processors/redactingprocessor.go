@@ -0,0 +1,38 @@
+package processors
+
+import (
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+)
+
+// RedactingProcessor strips the configured label and annotation keys from
+// a copy of each alert before handing it to next. Other processors sharing
+// the same alert are unaffected.
+type RedactingProcessor struct {
+	next        AlertProcessor
+	labels      []string
+	annotations []string
+}
+
+func NewRedactingProcessor(labels, annotations []string, next AlertProcessor) *RedactingProcessor {
+	return &RedactingProcessor{next: next, labels: labels, annotations: annotations}
+}
+
+func (rp *RedactingProcessor) Process(alert types.Alert) {
+	alert.Labels = without(alert.Labels, rp.labels)
+	alert.Annotations = without(alert.Annotations, rp.annotations)
+	rp.next.Process(alert)
+}
+
+func without(values map[string]string, keys []string) map[string]string {
+	if values == nil || len(keys) == 0 {
+		return values
+	}
+	stripped := make(map[string]string, len(values))
+	for k, v := range values {
+		stripped[k] = v
+	}
+	for _, k := range keys {
+		delete(stripped, k)
+	}
+	return stripped
+}
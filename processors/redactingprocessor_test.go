@@ -0,0 +1,32 @@
+package processors_test
+
+import (
+	"testing"
+
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactingProcessor(t *testing.T) {
+	external := &recordingProcessor{}
+	internal := &recordingProcessor{}
+	mp := processors.MultiProcessor{
+		processors.NewRedactingProcessor([]string{"api_key"}, []string{"internal_url"}, external),
+		internal,
+	}
+
+	mp.Process(handler.Alert{
+		Status:      "firing",
+		Labels:      map[string]string{"alertname": "Leak", "api_key": "s3cr3t"},
+		Annotations: map[string]string{"summary": "leak", "internal_url": "http://10.0.0.1"},
+	})
+
+	assert.Len(t, external.alerts, 1)
+	assert.Equal(t, map[string]string{"alertname": "Leak"}, external.alerts[0].Labels)
+	assert.Equal(t, map[string]string{"summary": "leak"}, external.alerts[0].Annotations)
+
+	assert.Len(t, internal.alerts, 1)
+	assert.Equal(t, "s3cr3t", internal.alerts[0].Labels["api_key"])
+	assert.Equal(t, "http://10.0.0.1", internal.alerts[0].Annotations["internal_url"])
+}
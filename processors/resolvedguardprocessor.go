@@ -0,0 +1,88 @@
+package processors
+
+import (
+	"github.com/igormishsky/prometheus-alerts-handler/metrics"
+	"github.com/igormishsky/prometheus-alerts-handler/redact"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// ResolvedGuardProcessor drops resolved alerts whose firing it never saw,
+// such as resolves for alerts that fired before a restart. Firing state is
+// held in memory, so during the grace period after startup resolves are
+// let through rather than judged against an incomplete record.
+//
+// Alerts that stop firing without ever resolving would otherwise be held
+// forever, so firing state not refreshed within ttl is forgotten. Since
+// Alertmanager resends firing alerts every repeat_interval, ttl should be
+// longer than that. Zero keeps state indefinitely.
+type ResolvedGuardProcessor struct {
+	next    AlertProcessor
+	graceTo time.Time
+	ttl     time.Duration
+
+	mu        sync.Mutex
+	firing    map[string]time.Time
+	nextSweep time.Time
+}
+
+func NewResolvedGuardProcessor(grace, ttl time.Duration, next AlertProcessor) *ResolvedGuardProcessor {
+	now := time.Now()
+	return &ResolvedGuardProcessor{
+		next:      next,
+		graceTo:   now.Add(grace),
+		ttl:       ttl,
+		firing:    make(map[string]time.Time),
+		nextSweep: now.Add(ttl),
+	}
+}
+
+func (rg *ResolvedGuardProcessor) Process(alert types.Alert) {
+	fingerprint := alert.Fingerprint()
+	now := time.Now()
+
+	rg.mu.Lock()
+	rg.sweep(now)
+	lastSeen, seen := rg.firing[fingerprint]
+	seen = seen && !rg.expired(lastSeen, now)
+	if alert.Status == "resolved" {
+		delete(rg.firing, fingerprint)
+	} else {
+		rg.firing[fingerprint] = now
+	}
+	rg.mu.Unlock()
+
+	if alert.Status == "resolved" && !seen && now.After(rg.graceTo) {
+		logrus.Info("Dropping resolved alert that was never seen firing:", redact.Alert(alert))
+		metrics.AlertsSuppressed.WithLabelValues("resolved_without_firing").Inc()
+		return
+	}
+	rg.next.Process(alert)
+}
+
+// Tracked returns the number of alerts currently held as firing.
+func (rg *ResolvedGuardProcessor) Tracked() int {
+	rg.mu.Lock()
+	defer rg.mu.Unlock()
+	return len(rg.firing)
+}
+
+func (rg *ResolvedGuardProcessor) expired(lastSeen, now time.Time) bool {
+	return rg.ttl > 0 && now.Sub(lastSeen) > rg.ttl
+}
+
+// sweep drops expired firing state. It walks the whole map, so it runs at
+// most once per ttl. The caller must hold rg.mu.
+func (rg *ResolvedGuardProcessor) sweep(now time.Time) {
+	if rg.ttl <= 0 || now.Before(rg.nextSweep) {
+		return
+	}
+	for fingerprint, lastSeen := range rg.firing {
+		if rg.expired(lastSeen, now) {
+			delete(rg.firing, fingerprint)
+		}
+	}
+	rg.nextSweep = now.Add(rg.ttl)
+}
@@ -0,0 +1,80 @@
+package processors_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/metrics"
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func guardAlert(status string) handler.Alert {
+	return handler.Alert{Status: status, Labels: map[string]string{"alertname": "DiskFull", "instance": "db-1"}}
+}
+
+func TestResolvedGuardProcessor_ResolveWithoutFiring(t *testing.T) {
+	rec := &recordingProcessor{}
+	rg := processors.NewResolvedGuardProcessor(0, 0, rec)
+	before := testutil.ToFloat64(metrics.AlertsSuppressed.WithLabelValues("resolved_without_firing"))
+
+	rg.Process(guardAlert("resolved"))
+
+	assert.Empty(t, rec.alerts)
+	assert.Equal(t, before+1, testutil.ToFloat64(metrics.AlertsSuppressed.WithLabelValues("resolved_without_firing")))
+}
+
+func TestResolvedGuardProcessor_ResolveAfterFiring(t *testing.T) {
+	rec := &recordingProcessor{}
+	rg := processors.NewResolvedGuardProcessor(0, 0, rec)
+
+	rg.Process(guardAlert("firing"))
+	rg.Process(guardAlert("resolved"))
+	rg.Process(guardAlert("resolved"))
+
+	assert.Len(t, rec.alerts, 2)
+	assert.Equal(t, "firing", rec.alerts[0].Status)
+	assert.Equal(t, "resolved", rec.alerts[1].Status)
+}
+
+func TestResolvedGuardProcessor_GracePeriod(t *testing.T) {
+	rec := &recordingProcessor{}
+	rg := processors.NewResolvedGuardProcessor(time.Hour, 0, rec)
+
+	rg.Process(guardAlert("resolved"))
+
+	assert.Len(t, rec.alerts, 1)
+}
+
+func TestResolvedGuardProcessor_ForgetsStaleFiring(t *testing.T) {
+	rec := &recordingProcessor{}
+	rg := processors.NewResolvedGuardProcessor(0, 50*time.Millisecond, rec)
+
+	rg.Process(guardAlert("firing"))
+	assert.Equal(t, 1, rg.Tracked())
+
+	time.Sleep(100 * time.Millisecond)
+	rg.Process(handler.Alert{Status: "firing", Labels: map[string]string{"alertname": "Other"}})
+	assert.Equal(t, 1, rg.Tracked())
+
+	rg.Process(guardAlert("resolved"))
+	assert.Len(t, rec.alerts, 2)
+	assert.Equal(t, "Other", rec.alerts[1].Labels["alertname"])
+}
+
+func TestResolvedGuardProcessor_RefreshedFiringIsKept(t *testing.T) {
+	rec := &recordingProcessor{}
+	rg := processors.NewResolvedGuardProcessor(0, 150*time.Millisecond, rec)
+
+	rg.Process(guardAlert("firing"))
+	time.Sleep(100 * time.Millisecond)
+	rg.Process(guardAlert("firing"))
+	time.Sleep(100 * time.Millisecond)
+	rg.Process(guardAlert("resolved"))
+
+	assert.Len(t, rec.alerts, 3)
+	assert.Equal(t, "resolved", rec.alerts[2].Status)
+	assert.Equal(t, 0, rg.Tracked())
+}
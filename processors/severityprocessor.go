@@ -0,0 +1,38 @@
+package processors
+
+import (
+	"fmt"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"github.com/sirupsen/logrus"
+	"strings"
+)
+
+var severityRanks = map[string]int{
+	"info":     1,
+	"warning":  2,
+	"critical": 3,
+}
+
+// SeverityProcessor forwards to Next only alerts whose severity label ranks
+// at or above a threshold (critical > warning > info). Alerts with a missing
+// or unknown severity are skipped.
+type SeverityProcessor struct {
+	next    AlertProcessor
+	minRank int
+}
+
+func NewSeverityProcessor(minSeverity string, next AlertProcessor) (*SeverityProcessor, error) {
+	rank, ok := severityRanks[strings.ToLower(minSeverity)]
+	if !ok {
+		return nil, fmt.Errorf("unknown min_severity %q", minSeverity)
+	}
+	return &SeverityProcessor{next: next, minRank: rank}, nil
+}
+
+func (sp *SeverityProcessor) Process(alert types.Alert) {
+	if severityRanks[strings.ToLower(alert.Labels["severity"])] < sp.minRank {
+		logrus.WithField("severity", alert.Labels["severity"]).Debug("Alert below processor min_severity, skipping")
+		return
+	}
+	sp.next.Process(alert)
+}
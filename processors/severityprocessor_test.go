@@ -0,0 +1,28 @@
+package processors_test
+
+import (
+	"testing"
+
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeverityProcessor(t *testing.T) {
+	rec := &recordingProcessor{}
+	p, err := processors.NewSeverityProcessor("warning", rec)
+	assert.NoError(t, err)
+
+	for _, severity := range []string{"info", "warning", "critical", "", "page"} {
+		p.Process(handler.Alert{Labels: map[string]string{"alertname": "Test", "severity": severity}})
+	}
+
+	assert.Len(t, rec.alerts, 2)
+	assert.Equal(t, "warning", rec.alerts[0].Labels["severity"])
+	assert.Equal(t, "critical", rec.alerts[1].Labels["severity"])
+}
+
+func TestNewSeverityProcessor_UnknownSeverity(t *testing.T) {
+	_, err := processors.NewSeverityProcessor("urgent", &recordingProcessor{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,25 @@
+package processors
+
+import (
+	"github.com/igormishsky/prometheus-alerts-handler/metrics"
+	"github.com/igormishsky/prometheus-alerts-handler/redact"
+	"github.com/igormishsky/prometheus-alerts-handler/silences"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"github.com/sirupsen/logrus"
+)
+
+// SilencingProcessor drops alerts matched by an active silence and forwards
+// the rest to Next.
+type SilencingProcessor struct {
+	Next     AlertProcessor
+	Silences *silences.Store
+}
+
+func (sp *SilencingProcessor) Process(alert types.Alert) {
+	if sp.Silences.Silenced(alert) {
+		logrus.Info("Alert silenced:", redact.Alert(alert))
+		metrics.AlertsSuppressed.WithLabelValues("silenced").Inc()
+		return
+	}
+	sp.Next.Process(alert)
+}
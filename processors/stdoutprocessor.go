@@ -0,0 +1,81 @@
+package processors
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	StdoutFormatJSON = "json"
+	StdoutFormatLine = "line"
+)
+
+// StdoutProcessor writes each alert to stdout, bypassing logrus, so log
+// shippers that scrape container output can pick alerts up directly.
+type StdoutProcessor struct {
+	format string
+	mu     sync.Mutex
+	out    io.Writer
+}
+
+// NewStdoutProcessor returns a processor writing alerts in the given format:
+// "json" (the default) for one JSON object per line, or "line" for a
+// key=value line.
+func NewStdoutProcessor(format string) (*StdoutProcessor, error) {
+	if format == "" {
+		format = StdoutFormatJSON
+	}
+	if format != StdoutFormatJSON && format != StdoutFormatLine {
+		return nil, fmt.Errorf("unknown stdout format %q", format)
+	}
+	return &StdoutProcessor{format: format, out: os.Stdout}, nil
+}
+
+func (sp *StdoutProcessor) Process(alert types.Alert) {
+	var line []byte
+	if sp.format == StdoutFormatLine {
+		line = []byte(formatAlertLine(alert))
+	} else {
+		var err error
+		if line, err = json.Marshal(alert); err != nil {
+			logrus.Error("Error marshalling alert for stdout:", err)
+			return
+		}
+	}
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	if _, err := sp.out.Write(append(line, '\n')); err != nil {
+		logrus.Error("Error writing alert to stdout:", err)
+	}
+}
+
+// formatAlertLine renders alert as "status=firing" followed by its labels
+// and then its annotations as key=value pairs, each group sorted by key.
+func formatAlertLine(alert types.Alert) string {
+	var b strings.Builder
+	b.WriteString("status=" + quoteValue(alert.Status))
+	writePairs(&b, "", alert.Labels)
+	writePairs(&b, "annotation_", alert.Annotations)
+	return b.String()
+}
+
+func writePairs(b *strings.Builder, prefix string, values map[string]string) {
+	for _, k := range types.SortedKeys(values) {
+		b.WriteString(" " + prefix + k + "=" + quoteValue(values[k]))
+	}
+}
+
+func quoteValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " \"=\t\n") {
+		return strconv.Quote(v)
+	}
+	return v
+}
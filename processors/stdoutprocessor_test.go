@@ -0,0 +1,68 @@
+package processors_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/stretchr/testify/assert"
+)
+
+var stdoutTestAlert = handler.Alert{
+	Status: "firing",
+	Labels: map[string]string{
+		"alertname": "DiskFull",
+		"severity":  "critical",
+	},
+	Annotations: map[string]string{
+		"summary": "Disk is full",
+	},
+}
+
+// captureStdout runs fn with os.Stdout redirected and returns what it wrote.
+func captureStdout(t *testing.T, fn func()) string {
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	w.Close()
+
+	out, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	return string(out)
+}
+
+func TestStdoutProcessor_JSON(t *testing.T) {
+	out := captureStdout(t, func() {
+		sp, err := processors.NewStdoutProcessor("")
+		assert.NoError(t, err)
+		sp.Process(stdoutTestAlert)
+	})
+
+	var decoded handler.Alert
+	assert.NoError(t, json.Unmarshal([]byte(out), &decoded))
+	assert.Equal(t, stdoutTestAlert, decoded)
+	assert.Equal(t, byte('\n'), out[len(out)-1])
+}
+
+func TestStdoutProcessor_Line(t *testing.T) {
+	out := captureStdout(t, func() {
+		sp, err := processors.NewStdoutProcessor(processors.StdoutFormatLine)
+		assert.NoError(t, err)
+		sp.Process(stdoutTestAlert)
+	})
+
+	assert.Equal(t, `status=firing alertname=DiskFull severity=critical annotation_summary="Disk is full"`+"\n", out)
+}
+
+func TestNewStdoutProcessor_InvalidFormat(t *testing.T) {
+	_, err := processors.NewStdoutProcessor("xml")
+	assert.Error(t, err)
+}
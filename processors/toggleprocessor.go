@@ -0,0 +1,71 @@
+package processors
+
+import (
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"github.com/sirupsen/logrus"
+	"sync/atomic"
+)
+
+// ToggleProcessor names a sink so it can be switched off and back on at
+// runtime without a reload. Disabled sinks skip alerts; the rest of the
+// pipeline is unaffected. Next is the sink behind its filters, Sink the
+// sink alone.
+type ToggleProcessor struct {
+	Name     string
+	Next     AlertProcessor
+	Sink     AlertProcessor
+	disabled int32
+}
+
+func (tp *ToggleProcessor) Process(alert types.Alert) {
+	if !tp.Enabled() {
+		logrus.WithField("processor", tp.Name).Debug("Processor disabled, skipping")
+		return
+	}
+	tp.Next.Process(alert)
+}
+
+// Inject delivers alert straight to Sink, bypassing Next's filters and the
+// enabled state, so a sink can be tried out before it is switched on.
+func (tp *ToggleProcessor) Inject(alert types.Alert) {
+	tp.Sink.Process(alert)
+}
+
+func (tp *ToggleProcessor) Enable() {
+	atomic.StoreInt32(&tp.disabled, 0)
+}
+
+func (tp *ToggleProcessor) Disable() {
+	atomic.StoreInt32(&tp.disabled, 1)
+}
+
+func (tp *ToggleProcessor) Enabled() bool {
+	return atomic.LoadInt32(&tp.disabled) == 0
+}
+
+// Registry holds the named sinks of a pipeline in registration order. It
+// is filled while the pipeline is built and only read afterwards.
+type Registry struct {
+	sinks []*ToggleProcessor
+}
+
+// Register wraps next, the filtered chain ending in sink, in a
+// ToggleProcessor named name and returns it.
+func (r *Registry) Register(name string, next, sink AlertProcessor) *ToggleProcessor {
+	tp := &ToggleProcessor{Name: name, Next: next, Sink: sink}
+	r.sinks = append(r.sinks, tp)
+	return tp
+}
+
+func (r *Registry) Get(name string) (*ToggleProcessor, bool) {
+	for _, tp := range r.sinks {
+		if tp.Name == name {
+			return tp, true
+		}
+	}
+	return nil, false
+}
+
+func (r *Registry) Sinks() []*ToggleProcessor {
+	return r.sinks
+}
@@ -0,0 +1,46 @@
+package processors_test
+
+import (
+	"testing"
+
+	"github.com/igormishsky/prometheus-alerts-handler/handler"
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToggleProcessor(t *testing.T) {
+	stdout, webhook := &recordingProcessor{}, &recordingProcessor{}
+	var reg processors.Registry
+	p := processors.MultiProcessor{reg.Register("stdout", stdout, stdout), reg.Register("webhook", webhook, webhook)}
+
+	tp, ok := reg.Get("webhook")
+	assert.True(t, ok)
+	tp.Disable()
+	p.Process(handler.Alert{Labels: map[string]string{"alertname": "Disabled"}})
+
+	tp.Enable()
+	p.Process(handler.Alert{Labels: map[string]string{"alertname": "Enabled"}})
+
+	assert.Len(t, stdout.alerts, 2)
+	assert.Len(t, webhook.alerts, 1)
+	assert.Equal(t, "Enabled", webhook.alerts[0].Labels["alertname"])
+
+	_, ok = reg.Get("missing")
+	assert.False(t, ok)
+}
+
+func TestToggleProcessor_InjectBypassesFilters(t *testing.T) {
+	rec := &recordingProcessor{}
+	severity, err := processors.NewSeverityProcessor("critical", rec)
+	assert.NoError(t, err)
+	var reg processors.Registry
+	tp := reg.Register("webhook", severity, rec)
+	tp.Disable()
+
+	alert := handler.Alert{Labels: map[string]string{"alertname": "Injected", "severity": "info"}}
+	tp.Process(alert)
+	assert.Empty(t, rec.alerts)
+
+	tp.Inject(alert)
+	assert.Len(t, rec.alerts, 1)
+}
@@ -0,0 +1,52 @@
+package redact
+
+import (
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"sync/atomic"
+)
+
+const Mask = "***"
+
+var keys atomic.Value
+
+func init() {
+	keys.Store(map[string]struct{}{})
+}
+
+// SetKeys sets the label and annotation keys whose values are masked when
+// alerts are logged.
+func SetKeys(names []string) {
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+	keys.Store(set)
+}
+
+// Alert returns a copy of alert that is safe to log, with the values of the
+// configured keys replaced by Mask.
+func Alert(alert types.Alert) types.Alert {
+	set := keys.Load().(map[string]struct{})
+	if len(set) == 0 {
+		return alert
+	}
+
+	alert.Labels = redactMap(alert.Labels, set)
+	alert.Annotations = redactMap(alert.Annotations, set)
+	return alert
+}
+
+func redactMap(values map[string]string, set map[string]struct{}) map[string]string {
+	if values == nil {
+		return nil
+	}
+
+	redacted := make(map[string]string, len(values))
+	for k, v := range values {
+		if _, ok := set[k]; ok {
+			v = Mask
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
@@ -0,0 +1,39 @@
+package redact_test
+
+import (
+	"testing"
+
+	"github.com/igormishsky/prometheus-alerts-handler/redact"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlert(t *testing.T) {
+	redact.SetKeys([]string{"password", "token"})
+	defer redact.SetKeys(nil)
+
+	alert := types.Alert{
+		Status: "firing",
+		Labels: map[string]string{
+			"alertname": "Leak",
+			"password":  "hunter2",
+		},
+		Annotations: map[string]string{
+			"token": "abc123",
+		},
+	}
+
+	redacted := redact.Alert(alert)
+
+	assert.Equal(t, "Leak", redacted.Labels["alertname"])
+	assert.Equal(t, redact.Mask, redacted.Labels["password"])
+	assert.Equal(t, redact.Mask, redacted.Annotations["token"])
+
+	assert.Equal(t, "hunter2", alert.Labels["password"], "original alert must not be modified")
+	assert.Equal(t, "abc123", alert.Annotations["token"], "original alert must not be modified")
+}
+
+func TestAlert_NoKeys(t *testing.T) {
+	alert := types.Alert{Labels: map[string]string{"password": "hunter2"}}
+	assert.Equal(t, alert, redact.Alert(alert))
+}
@@ -0,0 +1,77 @@
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/igormishsky/prometheus-alerts-handler/processors"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"io"
+	"time"
+)
+
+// Replay feeds stored alerts from r through p. The input is either a JSON
+// array of alerts or one alert object per line (JSONL). A positive rate
+// limits delivery to that many alerts per second. It returns the number of
+// alerts replayed.
+func Replay(r io.Reader, p processors.AlertProcessor, rate float64) (int, error) {
+	br := bufio.NewReader(r)
+	decoder := json.NewDecoder(br)
+
+	isArray, err := startsWithArray(br)
+	if err != nil {
+		return 0, err
+	}
+	if isArray {
+		if _, err := decoder.Token(); err != nil {
+			return 0, err
+		}
+	}
+
+	var throttle <-chan time.Time
+	if rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	count := 0
+	for {
+		if isArray && !decoder.More() {
+			break
+		}
+
+		var alert types.Alert
+		if err := decoder.Decode(&alert); err != nil {
+			if err == io.EOF && !isArray {
+				break
+			}
+			return count, err
+		}
+
+		if throttle != nil && count > 0 {
+			<-throttle
+		}
+		p.Process(alert)
+		count++
+	}
+
+	return count, nil
+}
+
+func startsWithArray(br *bufio.Reader) (bool, error) {
+	for {
+		b, err := br.Peek(1)
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			br.ReadByte()
+		default:
+			return b[0] == '[', nil
+		}
+	}
+}
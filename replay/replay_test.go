@@ -0,0 +1,71 @@
+package replay_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/igormishsky/prometheus-alerts-handler/replay"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingProcessor struct {
+	alerts []types.Alert
+}
+
+func (rp *recordingProcessor) Process(alert types.Alert) {
+	rp.alerts = append(rp.alerts, alert)
+}
+
+func TestReplay_Array(t *testing.T) {
+	input := `[
+		{"status": "firing", "labels": {"alertname": "A", "severity": "critical"}},
+		{"status": "resolved", "labels": {"alertname": "B", "severity": "warning"}}
+	]`
+
+	processor := &recordingProcessor{}
+	count, err := replay.Replay(strings.NewReader(input), processor, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, "A", processor.alerts[0].Labels["alertname"])
+	assert.Equal(t, "resolved", processor.alerts[1].Status)
+}
+
+func TestReplay_JSONL(t *testing.T) {
+	input := `{"status": "firing", "labels": {"alertname": "A"}}
+{"status": "firing", "labels": {"alertname": "B"}}
+{"status": "firing", "labels": {"alertname": "C"}}
+`
+
+	processor := &recordingProcessor{}
+	count, err := replay.Replay(strings.NewReader(input), processor, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, "C", processor.alerts[2].Labels["alertname"])
+}
+
+func TestReplay_Rate(t *testing.T) {
+	input := `{"status": "firing"}
+{"status": "firing"}
+{"status": "firing"}
+`
+
+	processor := &recordingProcessor{}
+	start := time.Now()
+	count, err := replay.Replay(strings.NewReader(input), processor, 20)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(90*time.Millisecond))
+}
+
+func TestReplay_InvalidInput(t *testing.T) {
+	processor := &recordingProcessor{}
+	count, err := replay.Replay(strings.NewReader(`[{"status": "firing"}, oops]`), processor, 0)
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, count)
+}
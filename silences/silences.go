@@ -0,0 +1,188 @@
+package silences
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Silence suppresses alerts whose labels equal all of Matchers until
+// ExpiresAt.
+type Silence struct {
+	ID        string            `json:"id"`
+	Matchers  map[string]string `json:"matchers"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+	Comment   string            `json:"comment,omitempty"`
+}
+
+func (s Silence) matches(alert types.Alert) bool {
+	for name, value := range s.Matchers {
+		if alert.Labels[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Store holds silences in memory. Expired silences are ignored and pruned
+// as new ones are added. A store opened with a path also saves its
+// silences there after every change.
+type Store struct {
+	mu       sync.RWMutex
+	silences map[string]Silence
+	path     string
+}
+
+func NewStore() *Store {
+	return &Store{silences: make(map[string]Silence)}
+}
+
+// OpenStore returns a store that loads its silences from the JSON file at
+// path, if it exists, and saves them there after every change. Silences
+// that expired while the handler was down are dropped. An empty path
+// returns an in-memory store.
+func OpenStore(path string) (*Store, error) {
+	st := NewStore()
+	if path == "" {
+		return st, nil
+	}
+	st.path = path
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var saved []Silence
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("parsing silences file %s: %v", path, err)
+	}
+	now := time.Now()
+	for _, s := range saved {
+		if s.ExpiresAt.After(now) {
+			st.silences[s.ID] = s
+		}
+	}
+	return st, nil
+}
+
+// Add stores s under a new ID and returns it.
+func (st *Store) Add(s Silence) (Silence, error) {
+	if len(s.Matchers) == 0 {
+		return Silence{}, fmt.Errorf("silence requires at least one matcher")
+	}
+	now := time.Now()
+	if !s.ExpiresAt.After(now) {
+		return Silence{}, fmt.Errorf("silence expiry must be in the future")
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return Silence{}, err
+	}
+	s.ID = hex.EncodeToString(id)
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for id, existing := range st.silences {
+		if !existing.ExpiresAt.After(now) {
+			delete(st.silences, id)
+		}
+	}
+	st.silences[s.ID] = s
+	st.save()
+	return s, nil
+}
+
+// List returns the active silences ordered by expiry.
+func (st *Store) List() []Silence {
+	now := time.Now()
+
+	st.mu.RLock()
+	active := make([]Silence, 0, len(st.silences))
+	for _, s := range st.silences {
+		if s.ExpiresAt.After(now) {
+			active = append(active, s)
+		}
+	}
+	st.mu.RUnlock()
+
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].ExpiresAt.Before(active[j].ExpiresAt)
+	})
+	return active
+}
+
+// Delete removes the silence with the given ID, reporting whether it
+// existed.
+func (st *Store) Delete(id string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	_, ok := st.silences[id]
+	if ok {
+		delete(st.silences, id)
+		st.save()
+	}
+	return ok
+}
+
+// Silenced reports whether an active silence matches alert.
+func (st *Store) Silenced(alert types.Alert) bool {
+	now := time.Now()
+
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	for _, s := range st.silences {
+		if s.ExpiresAt.After(now) && s.matches(alert) {
+			return true
+		}
+	}
+	return false
+}
+
+// save writes the silences to st.path, if set, through a temporary file so
+// a crash never leaves a partial file behind. It is called with st.mu held.
+// A failed write is logged; the silences stay in effect until a restart.
+func (st *Store) save() {
+	if st.path == "" {
+		return
+	}
+	saved := make([]Silence, 0, len(st.silences))
+	for _, s := range st.silences {
+		saved = append(saved, s)
+	}
+	if err := writeFileAtomic(st.path, saved); err != nil {
+		logrus.Error("Error saving silences to ", st.path, ": ", err)
+	}
+}
+
+func writeFileAtomic(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
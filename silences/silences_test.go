@@ -0,0 +1,110 @@
+package silences_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/igormishsky/prometheus-alerts-handler/silences"
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := silences.NewStore()
+
+	s, err := store.Add(silences.Silence{
+		Matchers:  map[string]string{"alertname": "DiskFull", "instance": "a"},
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, s.ID)
+	assert.Len(t, store.List(), 1)
+
+	assert.True(t, store.Silenced(types.Alert{Labels: map[string]string{"alertname": "DiskFull", "instance": "a", "severity": "critical"}}))
+	assert.False(t, store.Silenced(types.Alert{Labels: map[string]string{"alertname": "DiskFull", "instance": "b"}}))
+
+	assert.True(t, store.Delete(s.ID))
+	assert.False(t, store.Delete(s.ID))
+	assert.Empty(t, store.List())
+	assert.False(t, store.Silenced(types.Alert{Labels: map[string]string{"alertname": "DiskFull", "instance": "a"}}))
+}
+
+func TestStore_Validation(t *testing.T) {
+	store := silences.NewStore()
+
+	_, err := store.Add(silences.Silence{ExpiresAt: time.Now().Add(time.Hour)})
+	assert.Error(t, err)
+
+	_, err = store.Add(silences.Silence{
+		Matchers:  map[string]string{"alertname": "DiskFull"},
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	assert.Error(t, err)
+}
+
+func TestOpenStore_Persists(t *testing.T) {
+	dir, err := ioutil.TempDir("", "silences")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "silences.json")
+
+	store, err := silences.OpenStore(path)
+	assert.NoError(t, err)
+	kept, err := store.Add(silences.Silence{
+		Matchers:  map[string]string{"alertname": "DiskFull"},
+		ExpiresAt: time.Now().Add(time.Hour),
+		Comment:   "disk replacement",
+	})
+	assert.NoError(t, err)
+	deleted, err := store.Add(silences.Silence{
+		Matchers:  map[string]string{"alertname": "HighCPU"},
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.True(t, store.Delete(deleted.ID))
+
+	reopened, err := silences.OpenStore(path)
+	assert.NoError(t, err)
+	active := reopened.List()
+	assert.Len(t, active, 1)
+	assert.Equal(t, kept.ID, active[0].ID)
+	assert.Equal(t, "disk replacement", active[0].Comment)
+	assert.True(t, reopened.Silenced(types.Alert{Labels: map[string]string{"alertname": "DiskFull"}}))
+}
+
+func TestOpenStore_DropsExpired(t *testing.T) {
+	dir, err := ioutil.TempDir("", "silences")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "silences.json")
+
+	saved := `[
+		{"id": "expired", "matchers": {"alertname": "Old"}, "expiresAt": "2020-01-01T00:00:00Z"},
+		{"id": "active", "matchers": {"alertname": "New"}, "expiresAt": "` + time.Now().Add(time.Hour).Format(time.RFC3339) + `"}
+	]`
+	assert.NoError(t, ioutil.WriteFile(path, []byte(saved), 0600))
+
+	store, err := silences.OpenStore(path)
+	assert.NoError(t, err)
+	active := store.List()
+	assert.Len(t, active, 1)
+	assert.Equal(t, "active", active[0].ID)
+}
+
+func TestOpenStore_Errors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "silences")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := silences.OpenStore(filepath.Join(dir, "missing.json"))
+	assert.NoError(t, err)
+	assert.Empty(t, store.List())
+
+	invalid := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, ioutil.WriteFile(invalid, []byte("{"), 0600))
+	_, err = silences.OpenStore(invalid)
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"time"
+)
+
+type Alert struct {
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    string            `json:"startsAt,omitempty"`
+	EndsAt      string            `json:"endsAt,omitempty"`
+}
+
+// Fingerprint identifies an alert by its label set, independent of status,
+// annotations and timestamps.
+func (a Alert) Fingerprint() string {
+	h := sha256.New()
+	for _, name := range SortedKeys(a.Labels) {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write([]byte(a.Labels[name]))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// SortedKeys returns the keys of m in sorted order, for output that must
+// not depend on map iteration order.
+func SortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// MergeMissing returns values with the additions it does not already set.
+// values is never modified: when there is anything to add, the result is a
+// new map, so maps shared between alerts stay untouched.
+func MergeMissing(values, additions map[string]string) map[string]string {
+	if len(additions) == 0 {
+		return values
+	}
+
+	merged := make(map[string]string, len(values)+len(additions))
+	for k, v := range additions {
+		merged[k] = v
+	}
+	for k, v := range values {
+		merged[k] = v
+	}
+	return merged
+}
+
+// Duration reports how long the alert has been firing as of now, or for a
+// resolved alert with an EndsAt, how long it fired in total. ok is false
+// when StartsAt is missing or not RFC3339.
+func (a Alert) Duration(now time.Time) (d time.Duration, ok bool) {
+	start, err := time.Parse(time.RFC3339, a.StartsAt)
+	if err != nil {
+		return 0, false
+	}
+	if a.Status == "resolved" {
+		if end, err := time.Parse(time.RFC3339, a.EndsAt); err == nil {
+			now = end
+		}
+	}
+	if now.Before(start) {
+		return 0, true
+	}
+	return now.Sub(start), true
+}
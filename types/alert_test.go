@@ -0,0 +1,64 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/igormishsky/prometheus-alerts-handler/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlert_Fingerprint(t *testing.T) {
+	a := types.Alert{
+		Status: "firing",
+		Labels: map[string]string{"alertname": "Disk", "instance": "a"},
+	}
+	b := types.Alert{
+		Status:      "resolved",
+		Labels:      map[string]string{"instance": "a", "alertname": "Disk"},
+		Annotations: map[string]string{"summary": "disk full"},
+	}
+	c := types.Alert{
+		Labels: map[string]string{"alertname": "Disk", "instance": "b"},
+	}
+	d := types.Alert{
+		Labels: map[string]string{"alertname": "Diskinstance", "": "b"},
+	}
+
+	assert.Equal(t, a.Fingerprint(), b.Fingerprint())
+	assert.NotEqual(t, a.Fingerprint(), c.Fingerprint())
+	assert.NotEqual(t, c.Fingerprint(), d.Fingerprint())
+	assert.Len(t, a.Fingerprint(), 16)
+}
+
+func TestAlert_Duration(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	d, ok := types.Alert{Status: "firing", StartsAt: "2024-01-01T10:30:00Z"}.Duration(now)
+	assert.True(t, ok)
+	assert.Equal(t, 90*time.Minute, d)
+
+	d, ok = types.Alert{Status: "resolved", StartsAt: "2024-01-01T10:30:00Z", EndsAt: "2024-01-01T10:45:00Z"}.Duration(now)
+	assert.True(t, ok)
+	assert.Equal(t, 15*time.Minute, d)
+
+	_, ok = types.Alert{Status: "firing"}.Duration(now)
+	assert.False(t, ok)
+}
+
+func TestSortedKeys(t *testing.T) {
+	assert.Equal(t, []string{"alertname", "instance", "severity"},
+		types.SortedKeys(map[string]string{"severity": "warning", "alertname": "Disk", "instance": "a"}))
+	assert.Empty(t, types.SortedKeys(nil))
+}
+
+func TestMergeMissing(t *testing.T) {
+	values := map[string]string{"team": "storage"}
+	merged := types.MergeMissing(values, map[string]string{"team": "default", "env": "prod"})
+
+	assert.Equal(t, map[string]string{"team": "storage", "env": "prod"}, merged)
+	assert.Equal(t, map[string]string{"team": "storage"}, values)
+
+	assert.Equal(t, map[string]string{"env": "prod"}, types.MergeMissing(nil, map[string]string{"env": "prod"}))
+	assert.Nil(t, types.MergeMissing(nil, nil))
+}